@@ -21,13 +21,19 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 
 	"github.com/tmdc-io/tbls/cmdutil"
 	"github.com/tmdc-io/tbls/config"
 	"github.com/tmdc-io/tbls/datasource"
+	"github.com/tmdc-io/tbls/manifest"
+	"github.com/tmdc-io/tbls/output"
+	"github.com/tmdc-io/tbls/output/d2"
 	"github.com/tmdc-io/tbls/output/gviz"
 	"github.com/tmdc-io/tbls/output/md"
 	"github.com/tmdc-io/tbls/schema"
@@ -36,8 +42,10 @@ import (
 )
 
 var (
-	withoutER bool
-	rmDist    bool
+	withoutER   bool
+	rmDist      bool
+	parallel    int
+	incremental bool
 )
 
 // docCmd represents the doc command
@@ -90,6 +98,10 @@ var docCmd = &cobra.Command{
 			}
 		}
 
+		if incremental {
+			return withIncremental(s, c, force)
+		}
+
 		if !c.ER.Skip {
 			if err := withDot(s, c, force); err != nil {
 				return err
@@ -106,54 +118,208 @@ var docCmd = &cobra.Command{
 	},
 }
 
-func withDot(s *schema.Schema, c *config.Config, force bool) (e error) {
-	erFormat := c.ER.Format
-	outputPath := c.DocPath
-	fullPath, err := filepath.Abs(outputPath)
+// withIncremental generate only documents of the schema and tables changed since the last generation ( or whose files are missing ),
+// and remove documents of dropped tables. With force, all documents are regenerated.
+func withIncremental(s *schema.Schema, c *config.Config, force bool) error {
+	fullPath, err := filepath.Abs(c.DocPath)
 	if err != nil {
 		return errors.WithStack(err)
 	}
+	prev, err := manifest.Load(fullPath)
+	if err != nil {
+		return err
+	}
+	m, err := manifest.New(s, c)
+	if err != nil {
+		return err
+	}
+	schemaChanged := true
+	tables := s.Tables
+	if !force {
+		schemaChanged = m.SchemaChanged(prev, fullPath)
+		tables = m.ChangedTables(s, prev, fullPath)
+	}
 
-	if !force && outputErExists(s, fullPath) {
-		return errors.New("output ER diagram files already exists")
+	if !c.ER.Skip {
+		if err := outputER(s, c, schemaChanged, tables); err != nil {
+			return err
+		}
+	}
+	if err := md.OutputFiles(s, c, schemaChanged, tables); err != nil {
+		return err
 	}
 
-	err = os.MkdirAll(fullPath, 0755) // #nosec
+	for _, f := range m.StaleFiles(prev) {
+		if err := os.Remove(filepath.Join(fullPath, f)); err != nil && !os.IsNotExist(err) {
+			return errors.WithStack(err)
+		}
+		fmt.Printf("%s (removed)\n", filepath.Join(c.DocPath, f))
+	}
+
+	return m.Save(fullPath)
+}
+
+func withDot(s *schema.Schema, c *config.Config, force bool) error {
+	fullPath, err := filepath.Abs(c.DocPath)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	erFileName := fmt.Sprintf("schema.%s", erFormat)
-	fmt.Printf("%s\n", filepath.Join(outputPath, erFileName))
+	if !force && outputErExists(s, fullPath) {
+		return errors.New("output ER diagram files already exists")
+	}
 
-	file, err := os.OpenFile(filepath.Join(fullPath, erFileName), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
+	return outputER(s, c, true, s.Tables)
+}
+
+// outputER output ER diagram of the schema ( if schemaER is true ) and ER diagrams of the tables.
+func outputER(s *schema.Schema, c *config.Config, schemaER bool, tables []*schema.Table) error {
+	erFormat := c.ER.Format
+	outputPath := c.DocPath
+	fullPath, err := filepath.Abs(outputPath)
 	if err != nil {
 		return errors.WithStack(err)
 	}
-	g := gviz.New(c)
-	err = g.OutputSchema(file, s)
+
+	err = os.MkdirAll(fullPath, 0755) // #nosec
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
+	var o output.Output
+	if erFormat == "d2" {
+		// d2 has no clusters, pinned positions or lineage diagrams
+		if c.ER.GroupBy != "" {
+			_, _ = fmt.Fprintf(os.Stderr, "er.groupBy is not supported by the d2 format. tables are not grouped\n")
+		}
+		if c.ER.Layout.Enabled {
+			_, _ = fmt.Fprintf(os.Stderr, "er.layout is not supported by the d2 format. positions of tables are not pinned\n")
+		}
+		o = d2.New(c)
+	} else {
+		if c.ER.Layout.Enabled && c.ER.GroupBy != "" {
+			_, _ = fmt.Fprintf(os.Stderr, "er.layout can not pin tables in clusters of er.groupBy. positions of tables are not pinned\n")
+		}
+		g := gviz.New(c)
+		// render diagrams in worker processes because the graphviz library can render only one diagram at a time in a process
+		if n := c.Parallel; n > 1 || (n < 1 && runtime.NumCPU() > 1) {
+			if exe, err := os.Executable(); err == nil {
+				g.SetRenderCommand(exe, "render")
+			}
+		}
+		o = g
+	}
+	if schemaER {
+		if err := outputSchemaER(s, c, o); err != nil {
+			return err
+		}
+	}
+
 	// tables
-	for _, t := range s.Tables {
+	paths := make([][]string, len(tables))
+	err = output.Parallel(len(tables), c.Parallel, func(i int) error {
+		t := tables[i]
 		erFileName := fmt.Sprintf("%s.%s", t.Name, erFormat)
-		fmt.Printf("%s\n", filepath.Join(outputPath, erFileName))
+		if err := writeFile(filepath.Join(fullPath, erFileName), func(wr io.Writer) error {
+			return o.OutputTable(wr, t)
+		}); err != nil {
+			return err
+		}
+		paths[i] = append(paths[i], filepath.Join(outputPath, erFileName))
 
-		file, err := os.OpenFile(filepath.Join(fullPath, erFileName), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
-		if err != nil {
-			return errors.WithStack(err)
+		g, ok := o.(*gviz.Gviz)
+		if !ok || !t.HasLineage() {
+			return nil
 		}
-		err = g.OutputTable(file, t)
-		if err != nil {
-			return errors.WithStack(err)
+		lineageFileName := fmt.Sprintf("%s.lineage.%s", t.Name, erFormat)
+		if err := writeFile(filepath.Join(fullPath, lineageFileName), func(wr io.Writer) error {
+			return g.OutputLineage(wr, t)
+		}); err != nil {
+			return err
+		}
+		paths[i] = append(paths[i], filepath.Join(outputPath, lineageFileName))
+		return nil
+	})
+	for _, p := range paths {
+		for _, path := range p {
+			fmt.Printf("%s\n", path)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// outputSchemaER output ER diagram of the schema ( and the HTML embedding it ).
+func outputSchemaER(s *schema.Schema, c *config.Config, o output.Output) error {
+	erFormat := c.ER.Format
+	outputPath := c.DocPath
+	fullPath, err := filepath.Abs(outputPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	erFileName := fmt.Sprintf("schema.%s", erFormat)
+	fmt.Printf("%s\n", filepath.Join(outputPath, erFileName))
+
+	buf := &bytes.Buffer{}
+	if err := writeFile(filepath.Join(fullPath, erFileName), func(wr io.Writer) error {
+		if g, ok := o.(*gviz.Gviz); ok && c.ER.Layout.Enabled && c.ER.GroupBy == "" {
+			return outputStableSchemaER(io.MultiWriter(wr, buf), s, c, g)
+		}
+		return o.OutputSchema(io.MultiWriter(wr, buf), s)
+	}); err != nil {
+		return err
+	}
+
+	if g, ok := o.(*gviz.Gviz); ok && c.ER.HTML && erFormat == "svg" {
+		htmlFileName := "schema.html"
+		fmt.Printf("%s\n", filepath.Join(outputPath, htmlFileName))
+
+		if err := writeFile(filepath.Join(fullPath, htmlFileName), func(wr io.Writer) error {
+			return g.OutputHTML(wr, s.Name, buf.Bytes())
+		}); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// outputStableSchemaER output ER diagram of the schema with tables pinned to the positions in the layout file,
+// and save positions of added tables to the layout file.
+func outputStableSchemaER(wr io.Writer, s *schema.Schema, c *config.Config, g *gviz.Gviz) error {
+	path := c.LayoutPath()
+	l, err := gviz.LoadLayout(path)
+	if err != nil {
+		return err
+	}
+	l, err = g.OutputSchemaWithLayout(wr, s, l)
+	if err != nil {
+		return err
+	}
+	return l.Save(path)
+}
+
+// writeFile write output of fn to the file of path.
+func writeFile(path string, fn func(wr io.Writer) error) (e error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil && e == nil {
+			e = errors.WithStack(err)
+		}
+	}()
+	if err := fn(file); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
 func loadDocArgs(args []string) ([]config.Option, error) {
 	options := []config.Option{}
 	if len(args) > 2 {
@@ -170,6 +336,8 @@ func loadDocArgs(args []string) ([]config.Option, error) {
 		options = append(options, config.ERSkip(withoutER))
 	}
 	options = append(options, config.BaseUrl(baseUrl))
+	options = append(options, config.Direction(direction))
+	options = append(options, config.Parallel(parallel))
 	if len(args) == 2 {
 		options = append(options, config.DSNURL(args[0]))
 		options = append(options, config.DocPath(args[1]))
@@ -201,12 +369,15 @@ func init() {
 	docCmd.Flags().BoolVarP(&force, "force", "f", false, "force")
 	docCmd.Flags().BoolVarP(&sort, "sort", "", false, "sort")
 	docCmd.Flags().StringVarP(&configPath, "config", "c", "", "config file path")
-	docCmd.Flags().StringVarP(&erFormat, "er-format", "t", "", fmt.Sprintf("ER diagrams output format (png, svg, jpg, ...). default: %s", config.DefaultERFormat))
+	docCmd.Flags().StringVarP(&erFormat, "er-format", "t", "", fmt.Sprintf("ER diagrams output format (png, svg, jpg, d2, ...). default: %s", config.DefaultERFormat))
 	docCmd.Flags().BoolVarP(&withoutER, "without-er", "", false, "no generate ER diagrams")
 	docCmd.Flags().BoolVarP(&adjust, "adjust-table", "j", false, "adjust column width of table")
 	docCmd.Flags().StringVarP(&when, "when", "", "", "command execute condition")
 	docCmd.Flags().StringVarP(&baseUrl, "base-url", "b", "", "base url for links")
+	docCmd.Flags().StringVarP(&direction, "direction", "", "", "direction of relations to follow in the ER (both, parents, children)")
 	docCmd.Flags().BoolVarP(&rmDist, "rm-dist", "", false, "remove files in docPath before generating documents")
+	docCmd.Flags().BoolVarP(&incremental, "incremental", "", false, "regenerate only documents of changed tables and remove documents of dropped tables")
+	docCmd.Flags().IntVarP(&parallel, "parallel", "", 0, "number of tables to process in parallel. default: number of CPUs")
 	if err := docCmd.MarkZshCompPositionalArgumentFile(2); err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "%s\n", err)
 		os.Exit(1)
@@ -0,0 +1,99 @@
+/*
+Copyright © 2022 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/tmdc-io/tbls/cmdutil"
+	"github.com/tmdc-io/tbls/drift"
+)
+
+var (
+	envs    []string
+	dformat string
+)
+
+// driftCmd represents the drift command
+var driftCmd = &cobra.Command{
+	Use:   "drift --env NAME=DSN --env NAME=DSN [--env NAME=DSN ...]",
+	Short: "detect schema drift across environments",
+	Long:  `'tbls drift' analyzes the databases of all environments, compares them object by object ( tables, columns, indexes and constraints ), and shows which objects differ where.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if allow, err := cmdutil.IsAllowedToExecute(when); !allow || err != nil {
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+
+		es := []*drift.Env{}
+		for _, e := range envs {
+			kv := strings.SplitN(e, "=", 2)
+			if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+				return errors.Errorf("invalid environment '%s'. the format is NAME=DSN", e)
+			}
+			s, _, err := analyzeDSN(kv[1])
+			if err != nil {
+				return err
+			}
+			es = append(es, &drift.Env{Name: kv[0], Schema: s})
+		}
+
+		r, err := drift.Detect(es)
+		if err != nil {
+			return err
+		}
+
+		switch dformat {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(r); err != nil {
+				return errors.WithStack(err)
+			}
+		case "text", "":
+			if err := r.Write(os.Stdout); err != nil {
+				return err
+			}
+		default:
+			return errors.Errorf("unsupported format '%s'", dformat)
+		}
+		if r.Drifted() {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(driftCmd)
+	driftCmd.Flags().StringVarP(&configPath, "config", "c", "", "config file path")
+	driftCmd.Flags().StringArrayVarP(&envs, "env", "e", []string{}, "environment to compare (NAME=DSN)")
+	driftCmd.Flags().StringVarP(&dformat, "format", "t", "text", "output format (text, json)")
+	driftCmd.Flags().StringVarP(&when, "when", "", "", "command execute condition")
+}
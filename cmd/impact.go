@@ -0,0 +1,133 @@
+/*
+Copyright © 2022 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/tmdc-io/tbls/cmdutil"
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/datasource"
+	"github.com/tmdc-io/tbls/impact"
+)
+
+var (
+	iformat string
+	depth   int
+)
+
+// impactCmd represents the impact command
+var impactCmd = &cobra.Command{
+	Use:   "impact TABLE[.COLUMN] [DSN]",
+	Short: "list objects that depend on a table or a column",
+	Long:  `'tbls impact' lists objects ( tables with foreign keys, virtual relations and views ) that depend on a table or a column.`,
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if allow, err := cmdutil.IsAllowedToExecute(when); !allow || err != nil {
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+
+		c, err := config.New()
+		if err != nil {
+			return err
+		}
+
+		options := []config.Option{}
+		if len(args) == 2 {
+			options = append(options, config.DSNURL(args[1]))
+		}
+
+		if err := c.Load(configPath, options...); err != nil {
+			return err
+		}
+
+		s, err := datasource.Analyze(c.DSN)
+		if err != nil {
+			return err
+		}
+
+		if err := c.ModifySchema(s); err != nil {
+			return err
+		}
+
+		t, col, err := impact.FindTarget(s, args[0])
+		if err != nil {
+			return err
+		}
+		i := impact.Analyze(s, t, col, depth)
+
+		switch iformat {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(i); err != nil {
+				return errors.WithStack(err)
+			}
+		case "text", "":
+			printImpact(os.Stdout, i)
+		default:
+			return errors.Errorf("unsupported format '%s'", iformat)
+		}
+		return nil
+	},
+}
+
+func printImpact(wr io.Writer, i *impact.Impact) {
+	if i.Column != "" {
+		_, _ = fmt.Fprintf(wr, "%s.%s\n", i.Table, i.Column)
+	} else {
+		_, _ = fmt.Fprintf(wr, "%s\n", i.Table)
+	}
+	if len(i.Dependents) == 0 {
+		_, _ = fmt.Fprintln(wr, "  no dependents")
+		return
+	}
+	var walk func(deps []*impact.Dependent)
+	walk = func(deps []*impact.Dependent) {
+		for _, d := range deps {
+			name := d.Table
+			if len(d.Columns) > 0 {
+				name = fmt.Sprintf("%s (%s)", d.Table, strings.Join(d.Columns, ", "))
+			}
+			_, _ = fmt.Fprintf(wr, "%s%s [%s]\n", strings.Repeat("  ", d.Depth), name, d.Kind)
+			walk(d.Dependents)
+		}
+	}
+	walk(i.Dependents)
+}
+
+func init() {
+	rootCmd.AddCommand(impactCmd)
+	impactCmd.Flags().StringVarP(&configPath, "config", "c", "", "config file path")
+	impactCmd.Flags().StringVarP(&iformat, "format", "t", "text", "output format (text, json)")
+	impactCmd.Flags().IntVarP(&depth, "depth", "", 0, "depth of dependents to follow ( 0 is unlimited )")
+	impactCmd.Flags().StringVarP(&when, "when", "", "", "command execute condition")
+}
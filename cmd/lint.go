@@ -23,16 +23,22 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"reflect"
+	"strings"
 
 	"github.com/tmdc-io/tbls/cmdutil"
 	"github.com/tmdc-io/tbls/config"
 	"github.com/tmdc-io/tbls/datasource"
-	"github.com/labstack/gommon/color"
+	"github.com/tmdc-io/tbls/lint"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
+var (
+	lformat        string
+	baselinePath   string
+	updateBaseline bool
+)
+
 // lintCmd represents the lint command
 var lintCmd = &cobra.Command{
 	Use:   "lint [DSN] [DOC_PATH]",
@@ -46,6 +52,10 @@ var lintCmd = &cobra.Command{
 			return nil
 		}
 
+		if updateBaseline && baselinePath == "" {
+			return errors.New("--update-baseline requires --baseline")
+		}
+
 		c, err := config.New()
 		if err != nil {
 			return err
@@ -69,21 +79,33 @@ var lintCmd = &cobra.Command{
 			return err
 		}
 
-		l := reflect.Indirect(reflect.ValueOf(c.Lint))
-		t := l.Type()
+		ruleWarns, err := lint.Run(c, s)
+		if err != nil {
+			return err
+		}
 
-		ruleWarns := []config.RuleWarn{}
-		for i := 0; i < t.NumField(); i++ {
-			var v config.Rule
-			r := l.Field(i)
-			v = r.Interface().(config.Rule)
-			ruleWarns = append(ruleWarns, v.Check(s, s.NormalizeTableNames(c.LintExclude))...)
+		if updateBaseline {
+			if err := lint.NewBaseline(ruleWarns).Save(baselinePath); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(os.Stderr, "%d warnings are recorded to %s\n", len(ruleWarns), baselinePath)
+			return nil
 		}
-		if len(ruleWarns) > 0 {
-			for _, warn := range ruleWarns {
-				fmt.Println(fmt.Sprintf("%s%s", color.Cyan(warn.Target), color.White(fmt.Sprintf(": %s", warn.Message), color.B)))
+		if baselinePath != "" {
+			if _, err := os.Stat(baselinePath); os.IsNotExist(err) {
+				return errors.Errorf("baseline file '%s' does not exist. create it with --update-baseline", baselinePath)
+			}
+			b, err := lint.LoadBaseline(baselinePath)
+			if err != nil {
+				return err
 			}
-			fmt.Println(color.White(fmt.Sprintf("\n%d detected", len(ruleWarns)), color.B))
+			ruleWarns = b.Filter(ruleWarns)
+		}
+
+		if err := lint.Write(os.Stdout, lformat, ruleWarns); err != nil {
+			return err
+		}
+		if len(lint.Errors(ruleWarns)) > 0 {
 			os.Exit(1)
 		}
 
@@ -109,6 +131,9 @@ func loadLintArgs(args []string) ([]config.Option, error) {
 func init() {
 	rootCmd.AddCommand(lintCmd)
 	lintCmd.Flags().StringVarP(&configPath, "config", "c", "", "config file path")
+	lintCmd.Flags().StringVarP(&lformat, "format", "t", "text", fmt.Sprintf("output format (%s)", strings.Join(lint.Formats, ", ")))
+	lintCmd.Flags().StringVarP(&baselinePath, "baseline", "", "", "baseline file path. warnings recorded in the baseline are not reported")
+	lintCmd.Flags().BoolVarP(&updateBaseline, "update-baseline", "", false, "record current warnings to the baseline file ( create or overwrite )")
 	lintCmd.Flags().StringVarP(&when, "when", "", "", "command execute condition")
 	err := lintCmd.MarkZshCompPositionalArgumentFile(2)
 	if err != nil {
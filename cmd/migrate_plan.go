@@ -0,0 +1,124 @@
+/*
+Copyright © 2022 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/tmdc-io/tbls/cmdutil"
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/datasource"
+	"github.com/tmdc-io/tbls/migrate"
+	"github.com/tmdc-io/tbls/output/ddl"
+	"github.com/tmdc-io/tbls/schema"
+)
+
+var mformat string
+
+// migratePlanCmd represents the migrate-plan command
+var migratePlanCmd = &cobra.Command{
+	Use:   "migrate-plan FROM_DSN TO_DSN",
+	Short: "generate SQL that migrates a database schema to another schema",
+	Long:  `'tbls migrate-plan' generates ordered SQL statements ( ALTER TABLE, CREATE INDEX, DROP CONSTRAINT, ... ) that migrate the schema of FROM_DSN to the schema of TO_DSN, with warnings for destructive steps.`,
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if allow, err := cmdutil.IsAllowedToExecute(when); !allow || err != nil {
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+
+		from, fc, err := analyzeDSN(args[0])
+		if err != nil {
+			return err
+		}
+		to, tc, err := analyzeDSN(args[1])
+		if err != nil {
+			return err
+		}
+
+		d := ddl.DetectDialect(to)
+		if dialect != "" {
+			d, err = ddl.ParseDialect(dialect)
+			if err != nil {
+				return err
+			}
+		}
+		p := migrate.New(from, to, d)
+
+		switch mformat {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(p); err != nil {
+				return errors.WithStack(err)
+			}
+		case "text", "":
+			fdsn, err := fc.MaskedDSN()
+			if err != nil {
+				return err
+			}
+			tdsn, err := tc.MaskedDSN()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("-- from: %s\n-- to: %s\n\n", fdsn, tdsn)
+			if err := p.Write(os.Stdout); err != nil {
+				return err
+			}
+		default:
+			return errors.Errorf("unsupported format '%s'", mformat)
+		}
+		return nil
+	},
+}
+
+func analyzeDSN(dsn string) (*schema.Schema, *config.Config, error) {
+	c, err := config.New()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.Load(configPath, config.DSNURL(dsn)); err != nil {
+		return nil, nil, err
+	}
+	s, err := datasource.Analyze(c.DSN)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.ModifySchema(s); err != nil {
+		return nil, nil, err
+	}
+	return s, c, nil
+}
+
+func init() {
+	rootCmd.AddCommand(migratePlanCmd)
+	migratePlanCmd.Flags().StringVarP(&configPath, "config", "c", "", "config file path")
+	migratePlanCmd.Flags().StringVarP(&dialect, "dialect", "", "", "SQL dialect of statements (postgres, mysql, sqlite). default: dialect of TO_DSN")
+	migratePlanCmd.Flags().StringVarP(&mformat, "format", "t", "text", "output format (text, json)")
+	migratePlanCmd.Flags().StringVarP(&when, "when", "", "", "command execute condition")
+}
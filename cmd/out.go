@@ -23,15 +23,20 @@ package cmd
 import (
 	"io"
 	"os"
+	"strings"
 
 	"github.com/tmdc-io/tbls/cmdutil"
 	"github.com/tmdc-io/tbls/config"
 	"github.com/tmdc-io/tbls/datasource"
 	"github.com/tmdc-io/tbls/output"
 	tbls_config "github.com/tmdc-io/tbls/output/config"
+	"github.com/tmdc-io/tbls/output/d2"
+	"github.com/tmdc-io/tbls/output/dbml"
+	"github.com/tmdc-io/tbls/output/ddl"
 	"github.com/tmdc-io/tbls/output/dot"
 	"github.com/tmdc-io/tbls/output/gviz"
 	"github.com/tmdc-io/tbls/output/json"
+	"github.com/tmdc-io/tbls/output/lineage"
 	"github.com/tmdc-io/tbls/output/md"
 	"github.com/tmdc-io/tbls/output/plantuml"
 	"github.com/tmdc-io/tbls/output/xlsx"
@@ -45,6 +50,8 @@ var (
 	outPath   string
 	tableName string
 	distance  int
+	direction string
+	dialect   string
 )
 
 // outCmd represents the doc command
@@ -98,11 +105,26 @@ var outCmd = &cobra.Command{
 			o = xlsx.New(c)
 		case "plantuml":
 			o = plantuml.New(c)
+		case "d2":
+			o = d2.New(c)
+		case "dbml":
+			o = dbml.New(c)
+		case "ddl":
+			d := ddl.DetectDialect(s)
+			if dialect != "" {
+				d, err = ddl.ParseDialect(dialect)
+				if err != nil {
+					return err
+				}
+			}
+			o = ddl.New(c, d)
 		case "png", "svg", "jpg":
 			c.ER.Format = format
 			o = gviz.New(c)
 		case "config":
 			o = tbls_config.New(c)
+		case "lineage-dot", "lineage-mermaid", "lineage-json":
+			o = lineage.New(c, s, strings.TrimPrefix(format, "lineage-"))
 		default:
 			return errors.Errorf("unsupported format '%s'", format)
 		}
@@ -152,6 +174,7 @@ func loadOutArgs(args []string) ([]config.Option, error) {
 		options = append(options, config.Sort(sort))
 	}
 	options = append(options, config.Distance(distance))
+	options = append(options, config.Direction(direction))
 
 	if len(args) == 1 {
 		options = append(options, config.DSNURL(args[0]))
@@ -167,5 +190,7 @@ func init() {
 	outCmd.Flags().StringVarP(&outPath, "out", "o", "", "output file path")
 	outCmd.Flags().StringVar(&tableName, "table", "", "table name")
 	outCmd.Flags().IntVarP(&distance, "distance", "", config.DefaultDistance, "distance between tables that display associations in the ER")
+	outCmd.Flags().StringVarP(&direction, "direction", "", "", "direction of relations to follow in the ER (both, parents, children)")
+	outCmd.Flags().StringVarP(&dialect, "dialect", "", "", "SQL dialect of DDL (postgres, mysql, sqlite). default: dialect of the database")
 	outCmd.Flags().StringVarP(&when, "when", "", "", "command execute condition")
 }
@@ -0,0 +1,60 @@
+// Copyright © 2018 Ken'ichiro Oyama <k1lowxb@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/goccy/go-graphviz"
+	"github.com/tmdc-io/tbls/output/gviz"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	renderFormat string
+	renderLayout string
+	renderFont   string
+)
+
+// renderCmd represents the render command
+var renderCmd = &cobra.Command{
+	Use:    "render",
+	Short:  "render dot source",
+	Long:   `'tbls render' renders dot source from stdin to stdout. 'tbls doc' renders ER diagrams in parallel with it.`,
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		return gviz.Render(os.Stdout, b, graphviz.Format(renderFormat), graphviz.Layout(renderLayout), renderFont)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renderCmd)
+	renderCmd.Flags().StringVarP(&renderFormat, "format", "t", "svg", "image format")
+	renderCmd.Flags().StringVarP(&renderLayout, "layout", "", "", "layout engine. default: layout of the source")
+	renderCmd.Flags().StringVarP(&renderFont, "font", "", "", "font")
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2022 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/tmdc-io/tbls/cmdutil"
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/datasource"
+	"github.com/tmdc-io/tbls/schema"
+	"github.com/tmdc-io/tbls/server"
+)
+
+var (
+	addr     string
+	interval time.Duration
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve [DSN]",
+	Short: "serve documents over HTTP",
+	Long:  `'tbls serve' analyzes a database and serves HTML documents and ER diagrams over HTTP. It re-analyzes the database when the config file is changed or the interval has passed.`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if allow, err := cmdutil.IsAllowedToExecute(when); !allow || err != nil {
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+
+		options := []config.Option{}
+		if len(args) == 1 {
+			options = append(options, config.DSNURL(args[0]))
+		}
+
+		srv := server.New(func() (*config.Config, *schema.Schema, error) {
+			c, err := config.New()
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := c.Load(configPath, options...); err != nil {
+				return nil, nil, err
+			}
+			s, err := datasource.Analyze(c.DSN)
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := c.ModifySchema(s); err != nil {
+				return nil, nil, err
+			}
+			return c, s, nil
+		})
+		if err := srv.Reload(); err != nil {
+			return err
+		}
+
+		done := make(chan struct{})
+		defer close(done)
+		go srv.Watch(interval, done)
+
+		cmd.Printf("Serving documents on http://%s\n", addr)
+		if err := http.ListenAndServe(addr, srv); err != nil { // #nosec
+			return errors.WithStack(err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVarP(&configPath, "config", "c", "", "config file path")
+	serveCmd.Flags().StringVarP(&addr, "addr", "", "localhost:8080", "address to listen on")
+	serveCmd.Flags().DurationVarP(&interval, "interval", "", 0, "interval to re-analyze the database ( e.g. 1m ). 0 disables periodic re-analysis")
+	serveCmd.Flags().StringVarP(&when, "when", "", "", "command execute condition")
+}
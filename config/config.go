@@ -29,6 +29,9 @@ const DefaultERFormat = "svg"
 // DefaultDistance is the default distance between tables that display relations in the ER
 var DefaultDistance = 1
 
+// DefaultLayoutPath is the default path of the file that pins positions of tables in the ER diagram of the schema
+const DefaultLayoutPath = "schema.layout.json"
+
 // Config is tbls config
 type Config struct {
 	Name                   string                 `yaml:"name"`
@@ -48,6 +51,7 @@ type Config struct {
 	Templates              Templates              `yaml:"templates,omitempty"`
 	DetectVirtualRelations DetectVirtualRelations `yaml:"detectVirtualRelations,omitempty"`
 	BaseUrl                string                 `yaml:"baseUrl,omitempty"`
+	Parallel               int                    `yaml:"parallel,omitempty"`
 	RequiredVersion        string                 `yaml:"requiredVersion,omitempty"`
 	MergedDict             dict.Dict              `yaml:"-"`
 	Path                   string                 `yaml:"-"`
@@ -64,24 +68,40 @@ type Format struct {
 	Adjust bool `yaml:"adjust,omitempty"`
 	Sort   bool `yaml:"sort,omitempty"`
 	Number bool `yaml:"number,omitempty"`
+	// RelationsTable show relations of the table with cardinality in the Relations section
+	RelationsTable bool `yaml:"relationsTable,omitempty"`
 }
 
 // ER is er setting
 type ER struct {
-	Skip     bool   `yaml:"skip,omitempty"`
-	Format   string `yaml:"format,omitempty"`
-	Comment  bool   `yaml:"comment,omitempty"`
-	Distance *int   `yaml:"distance,omitempty"`
-	Font     string `yaml:"font,omitempty"`
+	Skip      bool   `yaml:"skip,omitempty"`
+	Format    string `yaml:"format,omitempty"`
+	Comment   bool   `yaml:"comment,omitempty"`
+	Distance  *int   `yaml:"distance,omitempty"`
+	Direction string `yaml:"direction,omitempty"`
+	Font      string `yaml:"font,omitempty"`
+	Link      bool   `yaml:"link,omitempty"`
+	HTML      bool   `yaml:"html,omitempty"`
+	GroupBy   string `yaml:"groupBy,omitempty"`
+	Layout    Layout `yaml:"layout,omitempty"`
+}
+
+// Layout is the setting for layout-stable ER diagram of the schema
+type Layout struct {
+	Enabled bool   `yaml:"enabled,omitempty"`
+	Seed    int    `yaml:"seed,omitempty"`
+	Path    string `yaml:"path,omitempty"`
 }
 
 // AdditionalRelation is the struct for table relation from yaml
 type AdditionalRelation struct {
-	Table         string   `yaml:"table"`
-	Columns       []string `yaml:"columns"`
-	ParentTable   string   `yaml:"parentTable"`
-	ParentColumns []string `yaml:"parentColumns"`
-	Def           string   `yaml:"def,omitempty"`
+	Table             string   `yaml:"table"`
+	Columns           []string `yaml:"columns"`
+	ParentTable       string   `yaml:"parentTable"`
+	ParentColumns     []string `yaml:"parentColumns"`
+	Def               string   `yaml:"def,omitempty"`
+	Cardinality       string   `yaml:"cardinality,omitempty"`
+	ParentCardinality string   `yaml:"parentCardinality,omitempty"`
 }
 
 // AdditionalComment is the struct for table relation from yaml
@@ -93,6 +113,14 @@ type AdditionalComment struct {
 	ConstraintComments map[string]string `yaml:"constraintComments,omitempty"`
 	TriggerComments    map[string]string `yaml:"triggerComments,omitempty"`
 	Labels             []string          `yaml:"labels,omitempty"`
+	IgnoreLint         []IgnoreLint      `yaml:"ignoreLint,omitempty"`
+}
+
+// IgnoreLint is the struct for lint suppression of the table ( or objects of the table )
+type IgnoreLint struct {
+	Rule    string   `yaml:"rule"`
+	Objects []string `yaml:"objects,omitempty"`
+	Reason  string   `yaml:"reason,omitempty"`
 }
 
 type DetectVirtualRelations struct {
@@ -165,6 +193,26 @@ func Distance(distance int) Option {
 	}
 }
 
+// Direction return Option set Config.ER.Direction
+func Direction(direction string) Option {
+	return func(c *Config) error {
+		if direction != "" {
+			c.ER.Direction = direction
+		}
+		return nil
+	}
+}
+
+// Parallel return Option set Config.Parallel
+func Parallel(parallel int) Option {
+	return func(c *Config) error {
+		if parallel > 0 {
+			c.Parallel = parallel
+		}
+		return nil
+	}
+}
+
 // BaseUrl return Option set Config.BaseUrl
 func BaseUrl(baseUrl string) Option {
 	return func(c *Config) error {
@@ -203,6 +251,10 @@ func (c *Config) Load(configPath string, options ...Option) error {
 		return err
 	}
 
+	if err := c.validate(); err != nil {
+		return err
+	}
+
 	if err := c.checkVersion(ver.Version); err != nil {
 		return err
 	}
@@ -237,6 +289,24 @@ func (c *Config) setDefault() error {
 	return nil
 }
 
+// validate check combinations of settings
+func (c *Config) validate() error {
+	if err := c.Lint.validateSeverity(); err != nil {
+		return err
+	}
+	for _, r := range []interface{ Validate() error }{c.Lint.TableNaming, c.Lint.ColumnNaming, c.Lint.IndexNaming, c.Lint.ConstraintNaming, c.Lint.ReservedWords} {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, r := range c.Lint.Custom {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *Config) checkVersion(sv string) error {
 	if sv == "dev" {
 		return nil
@@ -350,6 +420,9 @@ func (c *Config) ModifySchema(s *schema.Schema) error {
 	}
 	if c.DetectVirtualRelations.Enabled && SelectNamingStrategy(c.DetectVirtualRelations.Strategy) {
 		mergeDetectedRelations(s)
+		for _, r := range s.Relations {
+			r.SetDefaultCardinality()
+		}
 	}
 	c.mergeDictFromSchema(s)
 	return nil
@@ -365,6 +438,9 @@ func (c *Config) MergeAdditionalData(s *schema.Schema) error {
 	if err != nil {
 		return err
 	}
+	for _, r := range s.Relations {
+		r.SetDefaultCardinality()
+	}
 	return nil
 }
 
@@ -437,6 +513,18 @@ func excludeTableFromSchema(name string, s *schema.Schema) error {
 	return nil
 }
 
+// LayoutPath return the path of the layout file of the ER diagram of the schema ( relative to docPath )
+func (c *Config) LayoutPath() string {
+	p := c.ER.Layout.Path
+	if p == "" {
+		p = DefaultLayoutPath
+	}
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(c.DocPath, p)
+}
+
 // MaskedDSN return DSN mask password
 func (c *Config) MaskedDSN() (string, error) {
 	u, err := url.Parse(c.DSN.URL)
@@ -463,6 +551,18 @@ func mergeAdditionalRelations(s *schema.Schema, relations []AdditionalRelation)
 			relation.Def = "Additional Relation"
 		}
 		var err error
+		if r.Cardinality != "" {
+			relation.Cardinality, err = schema.ParseCardinality(r.Cardinality)
+			if err != nil {
+				return errors.Wrap(err, "failed to add relation")
+			}
+		}
+		if r.ParentCardinality != "" {
+			relation.ParentCardinality, err = schema.ParseCardinality(r.ParentCardinality)
+			if err != nil {
+				return errors.Wrap(err, "failed to add relation")
+			}
+		}
 		relation.Table, err = s.FindTableByName(r.Table)
 		if err != nil {
 			return errors.Wrap(err, "failed to add relation")
@@ -473,7 +573,6 @@ func mergeAdditionalRelations(s *schema.Schema, relations []AdditionalRelation)
 				return errors.Wrap(err, "failed to add relation")
 			}
 			relation.Columns = append(relation.Columns, column)
-			column.ParentRelations = append(column.ParentRelations, relation)
 		}
 		relation.ParentTable, err = s.FindTableByName(r.ParentTable)
 		if err != nil {
@@ -485,9 +584,31 @@ func mergeAdditionalRelations(s *schema.Schema, relations []AdditionalRelation)
 				return errors.Wrap(err, "failed to add relation")
 			}
 			relation.ParentColumns = append(relation.ParentColumns, column)
-			column.ChildRelations = append(column.ChildRelations, relation)
 		}
 
+		if er, err := s.FindRelation(relation.Columns, relation.ParentColumns); err == nil {
+			// If the relation already exists, override it with the fields of the additional relation.
+			er.Columns = relation.Columns
+			er.ParentColumns = relation.ParentColumns
+			er.Virtual = relation.Virtual
+			if r.Def != "" {
+				er.Def = relation.Def
+			}
+			if relation.Cardinality != "" {
+				er.Cardinality = relation.Cardinality
+			}
+			if relation.ParentCardinality != "" {
+				er.ParentCardinality = relation.ParentCardinality
+			}
+			continue
+		}
+
+		for _, c := range relation.Columns {
+			c.ParentRelations = append(c.ParentRelations, relation)
+		}
+		for _, c := range relation.ParentColumns {
+			c.ChildRelations = append(c.ChildRelations, relation)
+		}
 		s.Relations = append(s.Relations, relation)
 	}
 	return nil
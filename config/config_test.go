@@ -165,6 +165,12 @@ func TestMergeAditionalData(t *testing.T) {
 	if want := 1; len(s.Relations) != want {
 		t.Errorf("got %v\nwant %v", len(s.Relations), want)
 	}
+	if want := schema.ZeroOrMore; s.Relations[0].Cardinality != want {
+		t.Errorf("got %v\nwant %v", s.Relations[0].Cardinality, want)
+	}
+	if want := schema.ZeroOrOne; s.Relations[0].ParentCardinality != want {
+		t.Errorf("got %v\nwant %v", s.Relations[0].ParentCardinality, want)
+	}
 	users, _ := s.FindTableByName("users")
 	posts, _ := s.FindTableByName("posts")
 	title, _ := posts.FindColumnByName("title")
@@ -616,6 +622,146 @@ func Test_mergeDetectedRelations(t *testing.T) {
 	}
 }
 
+func TestMergeAdditionalRelations(t *testing.T) {
+	newSchema := func() *schema.Schema {
+		orders := &schema.Table{
+			Name: "orders",
+			Columns: []*schema.Column{
+				{Name: "id", Type: "int"},
+				{Name: "shop_id", Type: "int"},
+			},
+		}
+		items := &schema.Table{
+			Name: "order_items",
+			Columns: []*schema.Column{
+				{Name: "id", Type: "int"},
+				{Name: "order_id", Type: "int"},
+				{Name: "shop_id", Type: "int"},
+			},
+		}
+		r := &schema.Relation{
+			Table:             items,
+			Columns:           []*schema.Column{items.Columns[2], items.Columns[1]},
+			ParentTable:       orders,
+			ParentColumns:     []*schema.Column{orders.Columns[1], orders.Columns[0]},
+			Cardinality:       schema.ZeroOrMore,
+			ParentCardinality: schema.ExactlyOne,
+			Def:               "FOREIGN KEY (shop_id, order_id) REFERENCES orders(shop_id, id)",
+		}
+		for _, c := range r.Columns {
+			c.ParentRelations = append(c.ParentRelations, r)
+		}
+		for _, c := range r.ParentColumns {
+			c.ChildRelations = append(c.ChildRelations, r)
+		}
+		return &schema.Schema{
+			Name:      "testschema",
+			Tables:    []*schema.Table{orders, items},
+			Relations: []*schema.Relation{r},
+		}
+	}
+	tests := []struct {
+		name                  string
+		relation              AdditionalRelation
+		wantRelations         int
+		wantColumns           []string
+		wantParentColumns     []string
+		wantDef               string
+		wantVirtual           bool
+		wantCardinality       schema.Cardinality
+		wantParentCardinality schema.Cardinality
+	}{
+		{
+			"override composite relation",
+			AdditionalRelation{
+				Table:         "order_items",
+				Columns:       []string{"order_id", "shop_id"},
+				ParentTable:   "orders",
+				ParentColumns: []string{"id", "shop_id"},
+				Def:           "order items of the shop",
+				Cardinality:   "one_or_more",
+			},
+			1,
+			[]string{"order_id", "shop_id"},
+			[]string{"id", "shop_id"},
+			"order items of the shop",
+			true,
+			schema.OneOrMore,
+			schema.ExactlyOne,
+		},
+		{
+			"override composite relation without def",
+			AdditionalRelation{
+				Table:         "order_items",
+				Columns:       []string{"order_id", "shop_id"},
+				ParentTable:   "orders",
+				ParentColumns: []string{"id", "shop_id"},
+			},
+			1,
+			[]string{"order_id", "shop_id"},
+			[]string{"id", "shop_id"},
+			"FOREIGN KEY (shop_id, order_id) REFERENCES orders(shop_id, id)",
+			true,
+			schema.ZeroOrMore,
+			schema.ExactlyOne,
+		},
+		{
+			"add relation",
+			AdditionalRelation{
+				Table:         "order_items",
+				Columns:       []string{"order_id"},
+				ParentTable:   "orders",
+				ParentColumns: []string{"id"},
+			},
+			2,
+			[]string{"order_id"},
+			[]string{"id"},
+			"Additional Relation",
+			true,
+			"",
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSchema()
+			if err := mergeAdditionalRelations(s, []AdditionalRelation{tt.relation}); err != nil {
+				t.Fatal(err)
+			}
+			if len(s.Relations) != tt.wantRelations {
+				t.Fatalf("got %v\nwant %v", len(s.Relations), tt.wantRelations)
+			}
+			r := s.Relations[len(s.Relations)-1]
+			columns := []string{}
+			for _, c := range r.Columns {
+				columns = append(columns, c.Name)
+			}
+			if !reflect.DeepEqual(columns, tt.wantColumns) {
+				t.Errorf("got %v\nwant %v", columns, tt.wantColumns)
+			}
+			parentColumns := []string{}
+			for _, c := range r.ParentColumns {
+				parentColumns = append(parentColumns, c.Name)
+			}
+			if !reflect.DeepEqual(parentColumns, tt.wantParentColumns) {
+				t.Errorf("got %v\nwant %v", parentColumns, tt.wantParentColumns)
+			}
+			if r.Def != tt.wantDef {
+				t.Errorf("got %v\nwant %v", r.Def, tt.wantDef)
+			}
+			if r.Virtual != tt.wantVirtual {
+				t.Errorf("got %v\nwant %v", r.Virtual, tt.wantVirtual)
+			}
+			if r.Cardinality != tt.wantCardinality {
+				t.Errorf("got %v\nwant %v", r.Cardinality, tt.wantCardinality)
+			}
+			if r.ParentCardinality != tt.wantParentCardinality {
+				t.Errorf("got %v\nwant %v", r.ParentCardinality, tt.wantParentCardinality)
+			}
+		})
+	}
+}
+
 func TestCheckVersion(t *testing.T) {
 	tests := []struct {
 		v    string
@@ -639,3 +785,40 @@ func TestCheckVersion(t *testing.T) {
 		}
 	}
 }
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		modify  func(c *Config)
+		wantErr bool
+	}{
+		{func(c *Config) {}, false},
+		{func(c *Config) { c.ER.Layout.Enabled = true }, false},
+		{func(c *Config) { c.ER.GroupBy = "schema" }, false},
+		{func(c *Config) { c.Lint.RequirePrimaryKey = RequirePrimaryKey{Enabled: true, Severity: "warning"} }, false},
+		{func(c *Config) { c.Lint.RequirePrimaryKey = RequirePrimaryKey{Enabled: true, Severity: "warn"} }, true},
+		{func(c *Config) {
+			c.Lint.Custom = CustomRules{{Enabled: true, Name: "custom", Target: "table", Assert: "true", Severity: "fatal"}}
+		}, true},
+		{func(c *Config) { c.Lint.TableNaming = TableNaming{Enabled: true, Style: "snake_case"} }, false},
+		{func(c *Config) { c.Lint.TableNaming = TableNaming{Enabled: true, Style: "unknown"} }, true},
+		{func(c *Config) { c.Lint.ColumnNaming = ColumnNaming{Enabled: true, Pattern: "^[a-z"} }, true},
+		{func(c *Config) { c.Lint.ReservedWords = ReservedWords{Enabled: true, Dialect: "mysql"} }, false},
+		{func(c *Config) { c.Lint.ReservedWords = ReservedWords{Enabled: true, Dialect: "oracle"} }, true},
+		{func(c *Config) {
+			c.Lint.Custom = CustomRules{{Enabled: true, Name: "valid", Target: "table", Assert: "Table.Comment != ''"}}
+		}, false},
+		{func(c *Config) {
+			c.Lint.Custom = CustomRules{{Enabled: true, Name: "invalid", Target: "table", Assert: "Table.Unknown"}}
+		}, true},
+	}
+	for i, tt := range tests {
+		cfg, err := New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tt.modify(cfg)
+		if err := cfg.validate(); (err != nil) != tt.wantErr {
+			t.Errorf("TestValidate(%d): got %v\nwantErr %v", i, err, tt.wantErr)
+		}
+	}
+}
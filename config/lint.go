@@ -1,11 +1,17 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
+	"reflect"
 	"regexp"
 	"sort"
 	"strings"
+	"text/template"
 
+	"github.com/antonmedv/expr"
+	"github.com/antonmedv/expr/vm"
+	"github.com/pkg/errors"
 	"github.com/tmdc-io/tbls/schema"
 )
 
@@ -22,12 +28,90 @@ type Lint struct {
 	DuplicateRelations       DuplicateRelations       `yaml:"duplicateRelations"`
 	RequireForeignKeyIndex   RequireForeignKeyIndex   `yaml:"requireForeignKeyIndex"`
 	LabelStyleBigQuery       LabelStyleBigQuery       `yaml:"labelStyleBigQuery"`
+	RequirePrimaryKey        RequirePrimaryKey        `yaml:"requirePrimaryKey"`
+	ForeignKeyTypeMismatch   ForeignKeyTypeMismatch   `yaml:"foreignKeyTypeMismatch"`
+	VarcharWithoutLength     VarcharWithoutLength     `yaml:"varcharWithoutLength"`
+	TextIdentifier           TextIdentifier           `yaml:"textIdentifier"`
+	FloatMoney               FloatMoney               `yaml:"floatMoney"`
+	TimestampWithoutTimeZone TimestampWithoutTimeZone `yaml:"timestampWithoutTimeZone"`
+	InconsistentColumnType   InconsistentColumnType   `yaml:"inconsistentColumnType"`
+	DuplicateIndexes         DuplicateIndexes         `yaml:"duplicateIndexes"`
+	RedundantIndexes         RedundantIndexes         `yaml:"redundantIndexes"`
+	IndexCount               IndexCount               `yaml:"indexCount"`
+	UniqueIndexOnPrimaryKey  UniqueIndexOnPrimaryKey  `yaml:"uniqueIndexOnPrimaryKey"`
+	TableNaming              TableNaming              `yaml:"tableNaming"`
+	ColumnNaming             ColumnNaming             `yaml:"columnNaming"`
+	IndexNaming              IndexNaming              `yaml:"indexNaming"`
+	ConstraintNaming         ConstraintNaming         `yaml:"constraintNaming"`
+	ReservedWords            ReservedWords            `yaml:"reservedWords"`
+	Custom                   CustomRules              `yaml:"custom"`
+}
+
+// Severities of RuleWarn
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// ValidSeverity return the severity is one of error, warning or info
+func ValidSeverity(severity string) bool {
+	switch severity {
+	case SeverityError, SeverityWarning, SeverityInfo:
+		return true
+	}
+	return false
+}
+
+// validateSeverity check severities of all rules. Empty severity is the default ( error )
+func (l Lint) validateSeverity() error {
+	v := reflect.ValueOf(l)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if v.Field(i).Kind() != reflect.Struct {
+			continue
+		}
+		s := v.Field(i).FieldByName("Severity")
+		if !s.IsValid() || s.String() == "" || ValidSeverity(s.String()) {
+			continue
+		}
+		name := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		return errors.Errorf("invalid severity '%s' of lint rule '%s'. severity should be one of error, warning or info", s.String(), name)
+	}
+	for _, r := range l.Custom {
+		if r.Severity != "" && !ValidSeverity(r.Severity) {
+			return errors.Errorf("invalid severity '%s' of lint rule '%s'. severity should be one of error, warning or info", r.Severity, r.Name)
+		}
+	}
+	return nil
 }
 
 // RuleWarn is struct of Rule error
 type RuleWarn struct {
-	Target  string
-	Message string
+	Target   string    `json:"target"`
+	Message  string    `json:"message"`
+	Rule     string    `json:"rule"`
+	Severity string    `json:"severity"`
+	Location *Location `json:"location,omitempty"`
+}
+
+// Location is the location of the object of RuleWarn
+type Location struct {
+	Table  string `json:"table,omitempty"`
+	Object string `json:"object,omitempty"`
+	// Tables is the tables that have the object when the object is not in one table ( e.g. columns of the same name )
+	Tables []string `json:"tables,omitempty"`
+	Path   string   `json:"path,omitempty"`
+}
+
+// tableLocation return the location of the table
+func tableLocation(table string) *Location {
+	return &Location{Table: table}
+}
+
+// objectLocation return the location of the object ( column, index, constraint or trigger ) of the table
+func objectLocation(table, object string) *Location {
+	return &Location{Table: table, Object: object}
 }
 
 // Rule is interfece of `tbls lint` cop
@@ -39,6 +123,7 @@ type Rule interface {
 // RequireTableComment checks table comment
 type RequireTableComment struct {
 	Enabled      bool     `yaml:"enabled"`
+	Severity     string   `yaml:"severity"`
 	AllOrNothing bool     `yaml:"allOrNothing"`
 	Exclude      []string `yaml:"exclude"`
 }
@@ -68,8 +153,9 @@ func (r RequireTableComment) Check(s *schema.Schema, exclude []string) []RuleWar
 		}
 		if t.Comment == "" {
 			warns = append(warns, RuleWarn{
-				Target:  t.Name,
-				Message: msg,
+				Target:   t.Name,
+				Message:  msg,
+				Location: tableLocation(t.Name),
 			})
 			continue
 		}
@@ -84,6 +170,7 @@ func (r RequireTableComment) Check(s *schema.Schema, exclude []string) []RuleWar
 // RequireColumnComment checks column comment
 type RequireColumnComment struct {
 	Enabled       bool     `yaml:"enabled"`
+	Severity      string   `yaml:"severity"`
 	AllOrNothing  bool     `yaml:"allOrNothing"`
 	Exclude       []string `yaml:"exclude"`
 	ExcludeTables []string `yaml:"excludeTables"`
@@ -119,8 +206,9 @@ func (r RequireColumnComment) Check(s *schema.Schema, exclude []string) []RuleWa
 			}
 			if c.Comment == "" {
 				warns = append(warns, RuleWarn{
-					Target:  target,
-					Message: msg,
+					Target:   target,
+					Message:  msg,
+					Location: objectLocation(t.Name, c.Name),
 				})
 				continue
 			}
@@ -136,6 +224,7 @@ func (r RequireColumnComment) Check(s *schema.Schema, exclude []string) []RuleWa
 // RequireIndexComment checks index comment
 type RequireIndexComment struct {
 	Enabled       bool     `yaml:"enabled"`
+	Severity      string   `yaml:"severity"`
 	AllOrNothing  bool     `yaml:"allOrNothing"`
 	Exclude       []string `yaml:"exclude"`
 	ExcludeTables []string `yaml:"excludeTables"`
@@ -171,8 +260,9 @@ func (r RequireIndexComment) Check(s *schema.Schema, exclude []string) []RuleWar
 			}
 			if i.Comment == "" {
 				warns = append(warns, RuleWarn{
-					Target:  target,
-					Message: msg,
+					Target:   target,
+					Message:  msg,
+					Location: objectLocation(t.Name, i.Name),
 				})
 				continue
 			}
@@ -188,6 +278,7 @@ func (r RequireIndexComment) Check(s *schema.Schema, exclude []string) []RuleWar
 // RequireConstraintComment checks constraint comment
 type RequireConstraintComment struct {
 	Enabled       bool     `yaml:"enabled"`
+	Severity      string   `yaml:"severity"`
 	AllOrNothing  bool     `yaml:"allOrNothing"`
 	Exclude       []string `yaml:"exclude"`
 	ExcludeTables []string `yaml:"excludeTables"`
@@ -223,8 +314,9 @@ func (r RequireConstraintComment) Check(s *schema.Schema, exclude []string) []Ru
 			}
 			if c.Comment == "" {
 				warns = append(warns, RuleWarn{
-					Target:  target,
-					Message: msg,
+					Target:   target,
+					Message:  msg,
+					Location: objectLocation(t.Name, c.Name),
 				})
 				continue
 			}
@@ -240,6 +332,7 @@ func (r RequireConstraintComment) Check(s *schema.Schema, exclude []string) []Ru
 // RequireTriggerComment checks trigger comment
 type RequireTriggerComment struct {
 	Enabled       bool     `yaml:"enabled"`
+	Severity      string   `yaml:"severity"`
 	AllOrNothing  bool     `yaml:"allOrNothing"`
 	Exclude       []string `yaml:"exclude"`
 	ExcludeTables []string `yaml:"excludeTables"`
@@ -275,8 +368,9 @@ func (r RequireTriggerComment) Check(s *schema.Schema, exclude []string) []RuleW
 			}
 			if trig.Comment == "" {
 				warns = append(warns, RuleWarn{
-					Target:  target,
-					Message: msg,
+					Target:   target,
+					Message:  msg,
+					Location: objectLocation(t.Name, trig.Name),
 				})
 				continue
 			}
@@ -292,6 +386,7 @@ func (r RequireTriggerComment) Check(s *schema.Schema, exclude []string) []RuleW
 // UnrelatedTable checks isolated table
 type UnrelatedTable struct {
 	Enabled      bool     `yaml:"enabled"`
+	Severity     string   `yaml:"severity"`
 	AllOrNothing bool     `yaml:"allOrNothing"`
 	Exclude      []string `yaml:"exclude"`
 }
@@ -348,9 +443,10 @@ func (r UnrelatedTable) Check(s *schema.Schema, exclude []string) []RuleWarn {
 
 // ColumnCount checks table column count
 type ColumnCount struct {
-	Enabled bool     `yaml:"enabled"`
-	Max     int      `yaml:"max"`
-	Exclude []string `yaml:"exclude"`
+	Enabled  bool     `yaml:"enabled"`
+	Severity string   `yaml:"severity"`
+	Max      int      `yaml:"max"`
+	Exclude  []string `yaml:"exclude"`
 }
 
 // IsEnabled return Rule is enabled or not
@@ -376,8 +472,9 @@ func (r ColumnCount) Check(s *schema.Schema, exclude []string) []RuleWarn {
 		}
 		if len(t.Columns) > r.Max {
 			warns = append(warns, RuleWarn{
-				Target:  t.Name,
-				Message: fmt.Sprintf(msgFmt, len(t.Columns), r.Max),
+				Target:   t.Name,
+				Message:  fmt.Sprintf(msgFmt, len(t.Columns), r.Max),
+				Location: tableLocation(t.Name),
 			})
 		}
 	}
@@ -386,8 +483,9 @@ func (r ColumnCount) Check(s *schema.Schema, exclude []string) []RuleWarn {
 
 // RequireColumns checks if the table has specified columns
 type RequireColumns struct {
-	Enabled bool                   `yaml:"enabled"`
-	Columns []RequireColumnsColumn `yaml:"columns"`
+	Enabled  bool                   `yaml:"enabled"`
+	Severity string                 `yaml:"severity"`
+	Columns  []RequireColumnsColumn `yaml:"columns"`
 }
 
 // RequireColumnsColumn is required column
@@ -428,8 +526,9 @@ func (r RequireColumns) Check(s *schema.Schema, exclude []string) []RuleWarn {
 			}
 			if !exists {
 				warns = append(warns, RuleWarn{
-					Target:  t.Name,
-					Message: fmt.Sprintf(msgFmt, cc.Name),
+					Target:   t.Name,
+					Message:  fmt.Sprintf(msgFmt, cc.Name),
+					Location: tableLocation(t.Name),
 				})
 			}
 		}
@@ -439,7 +538,8 @@ func (r RequireColumns) Check(s *schema.Schema, exclude []string) []RuleWarn {
 
 // DuplicateRelations checks duplicate table relations
 type DuplicateRelations struct {
-	Enabled bool `yaml:"enabled"`
+	Enabled  bool   `yaml:"enabled"`
+	Severity string `yaml:"severity"`
 }
 
 // IsEnabled return Rule is enabled or not
@@ -477,8 +577,9 @@ func (r DuplicateRelations) Check(s *schema.Schema, exclude []string) []RuleWarn
 		key := [4]string{r.Table.Name, r.ParentTable.Name, fmt.Sprintf("%v", columns), fmt.Sprintf("%v", parentColumns)}
 		if _, dup := relations[key]; dup {
 			warns = append(warns, RuleWarn{
-				Target:  r.Table.Name,
-				Message: fmt.Sprintf(msgFmt, r.Table.Name, r.ParentTable.Name),
+				Target:   r.Table.Name,
+				Message:  fmt.Sprintf(msgFmt, r.Table.Name, r.ParentTable.Name),
+				Location: tableLocation(r.Table.Name),
 			})
 		}
 		relations[key] = true
@@ -489,8 +590,9 @@ func (r DuplicateRelations) Check(s *schema.Schema, exclude []string) []RuleWarn
 
 // RequireForeignKeyIndex checks if the foreign key columns have an index
 type RequireForeignKeyIndex struct {
-	Enabled bool     `yaml:"enabled"`
-	Exclude []string `yaml:"exclude"`
+	Enabled  bool     `yaml:"enabled"`
+	Severity string   `yaml:"severity"`
+	Exclude  []string `yaml:"exclude"`
 }
 
 // IsEnabled return Rule is enabled or not
@@ -529,8 +631,9 @@ func (r RequireForeignKeyIndex) Check(s *schema.Schema, exclude []string) []Rule
 				}
 				if !exist {
 					warns = append(warns, RuleWarn{
-						Target:  target,
-						Message: fmt.Sprintf(msgFmt, t.Name),
+						Target:   target,
+						Message:  fmt.Sprintf(msgFmt, t.Name),
+						Location: objectLocation(t.Name, c1),
 					})
 				}
 			}
@@ -542,8 +645,9 @@ func (r RequireForeignKeyIndex) Check(s *schema.Schema, exclude []string) []Rule
 
 // LabelStyleBigQuery checks if labels are in BigQuery style ( https://cloud.google.com/resource-manager/docs/creating-managing-labels#requirements )
 type LabelStyleBigQuery struct {
-	Enabled bool     `yaml:"enabled"`
-	Exclude []string `yaml:"exclude"`
+	Enabled  bool     `yaml:"enabled"`
+	Severity string   `yaml:"severity"`
+	Exclude  []string `yaml:"exclude"`
 }
 
 // IsEnabled return Rule is enabled or not
@@ -582,8 +686,9 @@ func (r LabelStyleBigQuery) Check(s *schema.Schema, exclude []string) []RuleWarn
 			if !checkLabelStyleBigQuery(l.Name) {
 				target := fmt.Sprintf("%s.Labels.%s", t.Name, l.Name)
 				warns = append(warns, RuleWarn{
-					Target:  target,
-					Message: fmt.Sprintf(msgFmt, l.Name, t.Name),
+					Target:   target,
+					Message:  fmt.Sprintf(msgFmt, l.Name, t.Name),
+					Location: tableLocation(t.Name),
 				})
 			}
 		}
@@ -616,3 +721,1260 @@ func checkLabelStyleBigQuery(label string) bool {
 	}
 	return true
 }
+
+// RequirePrimaryKey checks if the table has a primary key
+type RequirePrimaryKey struct {
+	Enabled  bool     `yaml:"enabled"`
+	Severity string   `yaml:"severity"`
+	Exclude  []string `yaml:"exclude"`
+}
+
+// IsEnabled return Rule is enabled or not
+func (r RequirePrimaryKey) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Check if the table ( not a view ) has a primary key
+func (r RequirePrimaryKey) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	if !r.IsEnabled() {
+		return warns
+	}
+	msg := "primary key required."
+
+	nt := s.NormalizeTableNames(r.Exclude)
+	for _, t := range s.Tables {
+		if contains(exclude, t.Name) {
+			continue
+		}
+		if contains(nt, t.Name) {
+			continue
+		}
+		if strings.Contains(strings.ToUpper(t.Type), "VIEW") {
+			continue
+		}
+		exist := false
+		for _, c := range t.Constraints {
+			if isPrimaryKey(c) {
+				exist = true
+				break
+			}
+		}
+		if !exist {
+			warns = append(warns, RuleWarn{
+				Target:   t.Name,
+				Message:  msg,
+				Location: tableLocation(t.Name),
+			})
+		}
+	}
+	return warns
+}
+
+// ForeignKeyTypeMismatch checks if the type of foreign key columns is the same as the type of referenced columns
+type ForeignKeyTypeMismatch struct {
+	Enabled  bool     `yaml:"enabled"`
+	Severity string   `yaml:"severity"`
+	Exclude  []string `yaml:"exclude"`
+}
+
+// IsEnabled return Rule is enabled or not
+func (r ForeignKeyTypeMismatch) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Check the type of foreign key columns and referenced columns
+func (r ForeignKeyTypeMismatch) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	if !r.IsEnabled() {
+		return warns
+	}
+	msgFmt := "column type does not match the referenced column. [%s: %s, %s.%s: %s]"
+
+	for _, rl := range s.Relations {
+		if contains(exclude, rl.Table.Name) || contains(exclude, rl.ParentTable.Name) {
+			continue
+		}
+		if len(rl.Columns) != len(rl.ParentColumns) {
+			continue
+		}
+		for i, c := range rl.Columns {
+			pc := rl.ParentColumns[i]
+			target := fmt.Sprintf("%s.%s", rl.Table.Name, c.Name)
+			if contains(r.Exclude, c.Name) || contains(r.Exclude, target) {
+				continue
+			}
+			if normalizeType(c.Type) == normalizeType(pc.Type) {
+				continue
+			}
+			warns = append(warns, RuleWarn{
+				Target:   target,
+				Message:  fmt.Sprintf(msgFmt, c.Name, c.Type, rl.ParentTable.Name, pc.Name, pc.Type),
+				Location: objectLocation(rl.Table.Name, c.Name),
+			})
+		}
+	}
+	return warns
+}
+
+// VarcharWithoutLength checks varchar columns without length
+type VarcharWithoutLength struct {
+	Enabled       bool     `yaml:"enabled"`
+	Severity      string   `yaml:"severity"`
+	Exclude       []string `yaml:"exclude"`
+	ExcludeTables []string `yaml:"excludeTables"`
+}
+
+// IsEnabled return Rule is enabled or not
+func (r VarcharWithoutLength) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Check varchar columns have length
+func (r VarcharWithoutLength) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	msg := "varchar without length."
+	return checkColumns(s, exclude, r.IsEnabled(), r.Exclude, r.ExcludeTables, func(t *schema.Table, c *schema.Column) string {
+		switch normalizeType(c.Type) {
+		case "varchar", "character varying", "nvarchar":
+			return msg
+		}
+		return ""
+	})
+}
+
+// TextIdentifier checks identifier columns ( `id` or `*_id` ) of text type
+type TextIdentifier struct {
+	Enabled       bool     `yaml:"enabled"`
+	Severity      string   `yaml:"severity"`
+	Exclude       []string `yaml:"exclude"`
+	ExcludeTables []string `yaml:"excludeTables"`
+}
+
+// IsEnabled return Rule is enabled or not
+func (r TextIdentifier) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Check identifier columns are not text type
+func (r TextIdentifier) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	msgFmt := "identifier column should not be %s."
+	return checkColumns(s, exclude, r.IsEnabled(), r.Exclude, r.ExcludeTables, func(t *schema.Table, c *schema.Column) string {
+		if !isIdentifierColumn(c.Name) {
+			return ""
+		}
+		switch typ := normalizeType(c.Type); typ {
+		case "text", "tinytext", "mediumtext", "longtext", "ntext", "clob":
+			return fmt.Sprintf(msgFmt, typ)
+		}
+		return ""
+	})
+}
+
+// FloatMoney checks money columns of floating-point type
+type FloatMoney struct {
+	Enabled       bool     `yaml:"enabled"`
+	Severity      string   `yaml:"severity"`
+	Columns       []string `yaml:"columns"`
+	Exclude       []string `yaml:"exclude"`
+	ExcludeTables []string `yaml:"excludeTables"`
+}
+
+// DefaultFloatMoneyColumns is the default names of money columns
+var DefaultFloatMoneyColumns = []string{"*price*", "*amount*", "*cost*", "*money*", "*balance*", "*fee*"}
+
+// IsEnabled return Rule is enabled or not
+func (r FloatMoney) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Check money columns are not floating-point type
+func (r FloatMoney) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	msgFmt := "money column should not be floating-point type. [%s]"
+	columns := []string{}
+	for _, c := range r.Columns {
+		columns = append(columns, strings.ToLower(c))
+	}
+	if len(columns) == 0 {
+		columns = DefaultFloatMoneyColumns
+	}
+	// names of columns are matched case-insensitively
+	return checkColumns(s, exclude, r.IsEnabled(), r.Exclude, r.ExcludeTables, func(t *schema.Table, c *schema.Column) string {
+		if !contains(columns, strings.ToLower(c.Name)) {
+			return ""
+		}
+		if !floatTypeRe.MatchString(normalizeType(c.Type)) {
+			return ""
+		}
+		return fmt.Sprintf(msgFmt, c.Type)
+	})
+}
+
+// TimestampWithoutTimeZone checks timestamp columns without time zone
+type TimestampWithoutTimeZone struct {
+	Enabled       bool     `yaml:"enabled"`
+	Severity      string   `yaml:"severity"`
+	Exclude       []string `yaml:"exclude"`
+	ExcludeTables []string `yaml:"excludeTables"`
+}
+
+// IsEnabled return Rule is enabled or not
+func (r TimestampWithoutTimeZone) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Check timestamp columns have time zone.
+// `timestamp` of MySQL and BigQuery is stored in UTC, so only `datetime` is checked.
+func (r TimestampWithoutTimeZone) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	msgFmt := "timestamp without time zone. [%s]"
+	utc := false
+	if s.Driver != nil {
+		switch strings.ToLower(s.Driver.Name) {
+		case "mysql", "mariadb", "bigquery":
+			utc = true
+		}
+	}
+	return checkColumns(s, exclude, r.IsEnabled(), r.Exclude, r.ExcludeTables, func(t *schema.Table, c *schema.Column) string {
+		typ := normalizeType(c.Type)
+		if utc && strings.HasPrefix(typ, "timestamp") {
+			return ""
+		}
+		if !timestampWithoutTimeZoneRe.MatchString(typ) {
+			return ""
+		}
+		return fmt.Sprintf(msgFmt, c.Type)
+	})
+}
+
+// InconsistentColumnType checks if columns of the same name have the same type across tables
+type InconsistentColumnType struct {
+	Enabled       bool     `yaml:"enabled"`
+	Severity      string   `yaml:"severity"`
+	Exclude       []string `yaml:"exclude"`
+	ExcludeTables []string `yaml:"excludeTables"`
+}
+
+// IsEnabled return Rule is enabled or not
+func (r InconsistentColumnType) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Check columns of the same name have the same type
+func (r InconsistentColumnType) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	if !r.IsEnabled() {
+		return warns
+	}
+	msgFmt := "column has different types across tables. [%s]"
+
+	names := []string{}
+	types := map[string][]string{}
+	tables := map[string]map[string][]string{}
+	nt := s.NormalizeTableNames(r.ExcludeTables)
+	for _, t := range s.Tables {
+		if contains(exclude, t.Name) {
+			continue
+		}
+		if contains(nt, t.Name) {
+			continue
+		}
+		for _, c := range t.Columns {
+			if contains(r.Exclude, c.Name) || contains(r.Exclude, fmt.Sprintf("%s.%s", t.Name, c.Name)) {
+				continue
+			}
+			typ := normalizeType(c.Type)
+			if _, ok := tables[c.Name]; !ok {
+				names = append(names, c.Name)
+				tables[c.Name] = map[string][]string{}
+			}
+			if _, ok := tables[c.Name][typ]; !ok {
+				types[c.Name] = append(types[c.Name], typ)
+			}
+			tables[c.Name][typ] = append(tables[c.Name][typ], t.Name)
+		}
+	}
+	for _, n := range names {
+		if len(types[n]) < 2 {
+			continue
+		}
+		ts := []string{}
+		ns := []string{}
+		for _, typ := range types[n] {
+			ts = append(ts, fmt.Sprintf("%s: %s", typ, strings.Join(tables[n][typ], ", ")))
+			ns = append(ns, tables[n][typ]...)
+		}
+		warns = append(warns, RuleWarn{
+			Target:   n,
+			Message:  fmt.Sprintf(msgFmt, strings.Join(ts, "; ")),
+			Location: &Location{Object: n, Tables: ns},
+		})
+	}
+	return warns
+}
+
+// checkColumns return warnings of columns that check returns the message
+func checkColumns(s *schema.Schema, exclude []string, enabled bool, excludeColumns, excludeTables []string, check func(t *schema.Table, c *schema.Column) string) []RuleWarn {
+	warns := []RuleWarn{}
+	if !enabled {
+		return warns
+	}
+	nt := s.NormalizeTableNames(excludeTables)
+	for _, t := range s.Tables {
+		if contains(exclude, t.Name) {
+			continue
+		}
+		if contains(nt, t.Name) {
+			continue
+		}
+		for _, c := range t.Columns {
+			target := fmt.Sprintf("%s.%s", t.Name, c.Name)
+			if contains(excludeColumns, c.Name) || contains(excludeColumns, target) {
+				continue
+			}
+			if msg := check(t, c); msg != "" {
+				warns = append(warns, RuleWarn{
+					Target:   target,
+					Message:  msg,
+					Location: objectLocation(t.Name, c.Name),
+				})
+			}
+		}
+	}
+	return warns
+}
+
+var (
+	spacesRe                   = regexp.MustCompile(`\s+`)
+	intDisplayWidthRe          = regexp.MustCompile(`^(tinyint|smallint|mediumint|int|integer|bigint)\(\d+\)`)
+	floatTypeRe                = regexp.MustCompile(`^(float|float4|float8|double|double precision|real|float32|float64)(\(.*\))?( unsigned)?$`)
+	timestampWithoutTimeZoneRe = regexp.MustCompile(`^(timestamp(\(\d+\))?( without time zone)?|datetime(\(\d+\))?|timestamp_ntz(\(\d+\))?)$`)
+	identifierColumnRe         = regexp.MustCompile(`(^|_)id$|[a-z0-9]Id$`)
+)
+
+// normalizeType return the lowercase type without display width of integer types
+func normalizeType(typ string) string {
+	typ = strings.TrimSpace(spacesRe.ReplaceAllString(strings.ToLower(typ), " "))
+	return intDisplayWidthRe.ReplaceAllString(typ, "$1")
+}
+
+func isIdentifierColumn(name string) bool {
+	return strings.EqualFold(name, "id") || identifierColumnRe.MatchString(name)
+}
+
+// DuplicateIndexes checks indexes that have the same columns in the same order
+type DuplicateIndexes struct {
+	Enabled       bool     `yaml:"enabled"`
+	Severity      string   `yaml:"severity"`
+	Exclude       []string `yaml:"exclude"`
+	ExcludeTables []string `yaml:"excludeTables"`
+}
+
+// IsEnabled return Rule is enabled or not
+func (r DuplicateIndexes) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Check duplicate indexes
+func (r DuplicateIndexes) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	msgFmt := "duplicate index of `%s`. [%s]"
+	return checkIndexPairs(s, exclude, r.IsEnabled(), r.Exclude, r.ExcludeTables, func(t *schema.Table, a, b *schema.Index) (*schema.Index, string) {
+		if indexColumns(a) != indexColumns(b) {
+			return nil, ""
+		}
+		// the primary key index is not reported
+		if isPrimaryKeyIndex(t, b) {
+			return a, fmt.Sprintf(msgFmt, b.Name, indexColumns(a))
+		}
+		return b, fmt.Sprintf(msgFmt, a.Name, indexColumns(a))
+	})
+}
+
+// RedundantIndexes checks indexes whose columns are a left-prefix of another index
+type RedundantIndexes struct {
+	Enabled       bool     `yaml:"enabled"`
+	Severity      string   `yaml:"severity"`
+	Exclude       []string `yaml:"exclude"`
+	ExcludeTables []string `yaml:"excludeTables"`
+}
+
+// IsEnabled return Rule is enabled or not
+func (r RedundantIndexes) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Check redundant indexes. Unique indexes are not redundant because they enforce uniqueness
+func (r RedundantIndexes) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	msgFmt := "index is a left-prefix of `%s`. [%s]"
+	isPrefix := func(short, long *schema.Index) bool {
+		if len(short.Columns) == 0 || len(short.Columns) >= len(long.Columns) {
+			return false
+		}
+		for i, c := range short.Columns {
+			if long.Columns[i] != c {
+				return false
+			}
+		}
+		return true
+	}
+	return checkIndexPairs(s, exclude, r.IsEnabled(), r.Exclude, r.ExcludeTables, func(t *schema.Table, a, b *schema.Index) (*schema.Index, string) {
+		switch {
+		case isPrefix(a, b) && !isUniqueIndex(t, a):
+			return a, fmt.Sprintf(msgFmt, b.Name, indexColumns(b))
+		case isPrefix(b, a) && !isUniqueIndex(t, b):
+			return b, fmt.Sprintf(msgFmt, a.Name, indexColumns(a))
+		}
+		return nil, ""
+	})
+}
+
+// IndexCount checks table index count
+type IndexCount struct {
+	Enabled  bool     `yaml:"enabled"`
+	Severity string   `yaml:"severity"`
+	Max      int      `yaml:"max"`
+	Exclude  []string `yaml:"exclude"`
+}
+
+// IsEnabled return Rule is enabled or not
+func (r IndexCount) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Check table index count
+func (r IndexCount) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	if !r.IsEnabled() {
+		return warns
+	}
+	msgFmt := "too many indexes. [%d/%d]"
+
+	nt := s.NormalizeTableNames(r.Exclude)
+	for _, t := range s.Tables {
+		if contains(exclude, t.Name) {
+			continue
+		}
+		if contains(nt, t.Name) {
+			continue
+		}
+		if len(t.Indexes) > r.Max {
+			warns = append(warns, RuleWarn{
+				Target:   t.Name,
+				Message:  fmt.Sprintf(msgFmt, len(t.Indexes), r.Max),
+				Location: tableLocation(t.Name),
+			})
+		}
+	}
+	return warns
+}
+
+// UniqueIndexOnPrimaryKey checks unique indexes that have the same columns as the primary key
+type UniqueIndexOnPrimaryKey struct {
+	Enabled       bool     `yaml:"enabled"`
+	Severity      string   `yaml:"severity"`
+	Exclude       []string `yaml:"exclude"`
+	ExcludeTables []string `yaml:"excludeTables"`
+}
+
+// IsEnabled return Rule is enabled or not
+func (r UniqueIndexOnPrimaryKey) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Check unique indexes duplicating the primary key
+func (r UniqueIndexOnPrimaryKey) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	if !r.IsEnabled() {
+		return warns
+	}
+	msgFmt := "unique index duplicates the primary key `%s`. [%s]"
+
+	nt := s.NormalizeTableNames(r.ExcludeTables)
+	for _, t := range s.Tables {
+		if contains(exclude, t.Name) {
+			continue
+		}
+		if contains(nt, t.Name) {
+			continue
+		}
+		var pk *schema.Constraint
+		for _, c := range t.Constraints {
+			if isPrimaryKey(c) {
+				pk = c
+				break
+			}
+		}
+		if pk == nil {
+			continue
+		}
+		pkCols := append([]string{}, pk.Columns...)
+		sort.Strings(pkCols)
+		for _, i := range t.Indexes {
+			target := fmt.Sprintf("%s.%s", t.Name, i.Name)
+			if contains(r.Exclude, i.Name) || contains(r.Exclude, target) {
+				continue
+			}
+			if isPrimaryKeyIndex(t, i) || !isUniqueIndex(t, i) {
+				continue
+			}
+			cols := append([]string{}, i.Columns...)
+			sort.Strings(cols)
+			if strings.Join(cols, ", ") != strings.Join(pkCols, ", ") {
+				continue
+			}
+			warns = append(warns, RuleWarn{
+				Target:   target,
+				Message:  fmt.Sprintf(msgFmt, pk.Name, indexColumns(i)),
+				Location: objectLocation(t.Name, i.Name),
+			})
+		}
+	}
+	return warns
+}
+
+// checkIndexPairs return warnings of indexes that check returns for each pair of indexes of the table
+func checkIndexPairs(s *schema.Schema, exclude []string, enabled bool, excludeIndexes, excludeTables []string, check func(t *schema.Table, a, b *schema.Index) (*schema.Index, string)) []RuleWarn {
+	warns := []RuleWarn{}
+	if !enabled {
+		return warns
+	}
+	nt := s.NormalizeTableNames(excludeTables)
+	for _, t := range s.Tables {
+		if contains(exclude, t.Name) {
+			continue
+		}
+		if contains(nt, t.Name) {
+			continue
+		}
+		warned := map[*schema.Index]struct{}{}
+		for i, a := range t.Indexes {
+			for _, b := range t.Indexes[i+1:] {
+				idx, msg := check(t, a, b)
+				if idx == nil {
+					continue
+				}
+				if _, ok := warned[idx]; ok {
+					continue
+				}
+				target := fmt.Sprintf("%s.%s", t.Name, idx.Name)
+				if contains(excludeIndexes, idx.Name) || contains(excludeIndexes, target) {
+					continue
+				}
+				warned[idx] = struct{}{}
+				warns = append(warns, RuleWarn{
+					Target:   target,
+					Message:  msg,
+					Location: objectLocation(t.Name, idx.Name),
+				})
+			}
+		}
+	}
+	return warns
+}
+
+func indexColumns(i *schema.Index) string {
+	return strings.Join(i.Columns, ", ")
+}
+
+// isPrimaryKeyIndex return true if the index is created by the primary key
+func isPrimaryKeyIndex(t *schema.Table, i *schema.Index) bool {
+	if strings.Contains(strings.ToUpper(i.Def), "PRIMARY KEY") {
+		return true
+	}
+	for _, c := range t.Constraints {
+		if c.Name == i.Name && isPrimaryKey(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUniqueIndex return true if the index is unique or created by a unique constraint ( or the primary key )
+func isUniqueIndex(t *schema.Table, i *schema.Index) bool {
+	def := strings.ToUpper(i.Def)
+	if strings.Contains(def, "UNIQUE") || strings.Contains(def, "PRIMARY KEY") {
+		return true
+	}
+	for _, c := range t.Constraints {
+		if c.Name != i.Name {
+			continue
+		}
+		switch strings.ToUpper(c.Type) {
+		case "UNIQUE", "UNIQUE KEY", "U", "PRIMARY KEY", "P":
+			return true
+		}
+	}
+	return false
+}
+
+// TableNaming checks table names
+type TableNaming struct {
+	Enabled  bool     `yaml:"enabled"`
+	Severity string   `yaml:"severity"`
+	Style    string   `yaml:"style"`
+	Pattern  string   `yaml:"pattern"`
+	Number   string   `yaml:"number"`
+	Exclude  []string `yaml:"exclude"`
+}
+
+// IsEnabled return Rule is enabled or not
+func (r TableNaming) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Validate check the style, the pattern and the number
+func (r TableNaming) Validate() error {
+	if !r.IsEnabled() {
+		return nil
+	}
+	_, err := r.naming()
+	return errors.Wrap(err, "invalid naming rule 'tableNaming'")
+}
+
+func (r TableNaming) naming() (*naming, error) {
+	switch r.Number {
+	case "", "singular", "plural":
+	default:
+		return nil, errors.Errorf("unsupported number '%s'", r.Number)
+	}
+	return newNaming("table", r.Style, r.Pattern)
+}
+
+// Check table names follow the style, the pattern and the number ( singular or plural )
+func (r TableNaming) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	if !r.IsEnabled() {
+		return warns
+	}
+	n, err := r.naming()
+	if err != nil {
+		return invalidNamingRule("tableNaming", err)
+	}
+
+	nt := s.NormalizeTableNames(r.Exclude)
+	for _, t := range s.Tables {
+		if contains(exclude, t.Name) {
+			continue
+		}
+		if contains(nt, t.Name) {
+			continue
+		}
+		name := unqualifiedName(t.Name)
+		msgs := n.check(name)
+		if r.Number != "" {
+			w := lastWord(name)
+			if (r.Number == "singular" && !pluralizeClient.IsSingular(w)) || (r.Number == "plural" && !pluralizeClient.IsPlural(w)) {
+				msgs = append(msgs, fmt.Sprintf("table name should be %s.", r.Number))
+			}
+		}
+		for _, msg := range msgs {
+			warns = append(warns, RuleWarn{
+				Target:   t.Name,
+				Message:  msg,
+				Location: tableLocation(t.Name),
+			})
+		}
+	}
+	return warns
+}
+
+// ColumnNaming checks column names
+type ColumnNaming struct {
+	Enabled       bool     `yaml:"enabled"`
+	Severity      string   `yaml:"severity"`
+	Style         string   `yaml:"style"`
+	Pattern       string   `yaml:"pattern"`
+	Exclude       []string `yaml:"exclude"`
+	ExcludeTables []string `yaml:"excludeTables"`
+}
+
+// IsEnabled return Rule is enabled or not
+func (r ColumnNaming) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Validate check the style and the pattern
+func (r ColumnNaming) Validate() error {
+	if !r.IsEnabled() {
+		return nil
+	}
+	_, err := newNaming("column", r.Style, r.Pattern)
+	return errors.Wrap(err, "invalid naming rule 'columnNaming'")
+}
+
+// Check column names follow the style and the pattern
+func (r ColumnNaming) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	if !r.IsEnabled() {
+		return warns
+	}
+	n, err := newNaming("column", r.Style, r.Pattern)
+	if err != nil {
+		return invalidNamingRule("columnNaming", err)
+	}
+
+	nt := s.NormalizeTableNames(r.ExcludeTables)
+	for _, t := range s.Tables {
+		if contains(exclude, t.Name) {
+			continue
+		}
+		if contains(nt, t.Name) {
+			continue
+		}
+		for _, c := range t.Columns {
+			target := fmt.Sprintf("%s.%s", t.Name, c.Name)
+			if contains(r.Exclude, c.Name) || contains(r.Exclude, target) {
+				continue
+			}
+			msgs := n.check(c.Name)
+			for _, msg := range msgs {
+				warns = append(warns, RuleWarn{
+					Target:   target,
+					Message:  msg,
+					Location: objectLocation(t.Name, c.Name),
+				})
+			}
+		}
+	}
+	return warns
+}
+
+// IndexNaming checks index names. Indexes created by constraints are checked by ConstraintNaming
+type IndexNaming struct {
+	Enabled       bool     `yaml:"enabled"`
+	Severity      string   `yaml:"severity"`
+	Style         string   `yaml:"style"`
+	Pattern       string   `yaml:"pattern"`
+	Prefix        string   `yaml:"prefix"`
+	Exclude       []string `yaml:"exclude"`
+	ExcludeTables []string `yaml:"excludeTables"`
+}
+
+// IsEnabled return Rule is enabled or not
+func (r IndexNaming) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Validate check the style and the pattern
+func (r IndexNaming) Validate() error {
+	if !r.IsEnabled() {
+		return nil
+	}
+	_, err := newNaming("index", r.Style, r.Pattern)
+	return errors.Wrap(err, "invalid naming rule 'indexNaming'")
+}
+
+// Check index names follow the style, the pattern and the prefix
+func (r IndexNaming) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	if !r.IsEnabled() {
+		return warns
+	}
+	n, err := newNaming("index", r.Style, r.Pattern)
+	if err != nil {
+		return invalidNamingRule("indexNaming", err)
+	}
+	msgFmt := "index name should start with `%s`."
+
+	nt := s.NormalizeTableNames(r.ExcludeTables)
+	for _, t := range s.Tables {
+		if contains(exclude, t.Name) {
+			continue
+		}
+		if contains(nt, t.Name) {
+			continue
+		}
+	INDEX:
+		for _, i := range t.Indexes {
+			for _, c := range t.Constraints {
+				if c.Name == i.Name {
+					continue INDEX
+				}
+			}
+			target := fmt.Sprintf("%s.%s", t.Name, i.Name)
+			if contains(r.Exclude, i.Name) || contains(r.Exclude, target) {
+				continue
+			}
+			msgs := n.check(i.Name)
+			if r.Prefix != "" && !strings.HasPrefix(i.Name, r.Prefix) {
+				msgs = append(msgs, fmt.Sprintf(msgFmt, r.Prefix))
+			}
+			for _, msg := range msgs {
+				warns = append(warns, RuleWarn{
+					Target:   target,
+					Message:  msg,
+					Location: objectLocation(t.Name, i.Name),
+				})
+			}
+		}
+	}
+	return warns
+}
+
+// ConstraintNaming checks constraint names
+type ConstraintNaming struct {
+	Enabled          bool     `yaml:"enabled"`
+	Severity         string   `yaml:"severity"`
+	Style            string   `yaml:"style"`
+	Pattern          string   `yaml:"pattern"`
+	ForeignKeyPrefix string   `yaml:"foreignKeyPrefix"`
+	Exclude          []string `yaml:"exclude"`
+	ExcludeTables    []string `yaml:"excludeTables"`
+}
+
+// IsEnabled return Rule is enabled or not
+func (r ConstraintNaming) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Validate check the style and the pattern
+func (r ConstraintNaming) Validate() error {
+	if !r.IsEnabled() {
+		return nil
+	}
+	_, err := newNaming("constraint", r.Style, r.Pattern)
+	return errors.Wrap(err, "invalid naming rule 'constraintNaming'")
+}
+
+// Check constraint names follow the style, the pattern and the prefix of foreign keys
+func (r ConstraintNaming) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	if !r.IsEnabled() {
+		return warns
+	}
+	n, err := newNaming("constraint", r.Style, r.Pattern)
+	if err != nil {
+		return invalidNamingRule("constraintNaming", err)
+	}
+	msgFmt := "foreign key name should start with `%s`."
+
+	nt := s.NormalizeTableNames(r.ExcludeTables)
+	for _, t := range s.Tables {
+		if contains(exclude, t.Name) {
+			continue
+		}
+		if contains(nt, t.Name) {
+			continue
+		}
+		for _, c := range t.Constraints {
+			target := fmt.Sprintf("%s.%s", t.Name, c.Name)
+			if contains(r.Exclude, c.Name) || contains(r.Exclude, target) {
+				continue
+			}
+			msgs := n.check(c.Name)
+			if r.ForeignKeyPrefix != "" && isForeignKey(c) && !strings.HasPrefix(c.Name, r.ForeignKeyPrefix) {
+				msgs = append(msgs, fmt.Sprintf(msgFmt, r.ForeignKeyPrefix))
+			}
+			for _, msg := range msgs {
+				warns = append(warns, RuleWarn{
+					Target:   target,
+					Message:  msg,
+					Location: objectLocation(t.Name, c.Name),
+				})
+			}
+		}
+	}
+	return warns
+}
+
+// ReservedWords checks if table names and column names are reserved words of the dialect
+type ReservedWords struct {
+	Enabled       bool     `yaml:"enabled"`
+	Severity      string   `yaml:"severity"`
+	Dialect       string   `yaml:"dialect"`
+	Words         []string `yaml:"words"`
+	Exclude       []string `yaml:"exclude"`
+	ExcludeTables []string `yaml:"excludeTables"`
+}
+
+// IsEnabled return Rule is enabled or not
+func (r ReservedWords) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Validate return error if the dialect is not supported
+func (r ReservedWords) Validate() error {
+	if !r.IsEnabled() || r.Dialect == "" {
+		return nil
+	}
+	if _, ok := reservedWords[r.Dialect]; !ok {
+		return errors.Errorf("invalid rule 'reservedWords'. unsupported dialect '%s'", r.Dialect)
+	}
+	return nil
+}
+
+// Check if table names and column names are reserved words
+func (r ReservedWords) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	if !r.IsEnabled() {
+		return warns
+	}
+	msgFmt := "`%s` is a reserved word in %s."
+
+	dialect := r.Dialect
+	if dialect == "" {
+		driver := ""
+		if s.Driver != nil {
+			driver = s.Driver.Name
+		}
+		dialect = reservedWordsDialect(driver)
+	}
+	// the dialect is validated on loading config
+	words := reservedWords[dialect]
+	reserved := map[string]struct{}{}
+	for _, w := range words {
+		reserved[w] = struct{}{}
+	}
+	for _, w := range r.Words {
+		reserved[strings.ToUpper(w)] = struct{}{}
+	}
+	isReserved := func(name string) bool {
+		_, ok := reserved[strings.ToUpper(name)]
+		return ok
+	}
+
+	nt := s.NormalizeTableNames(r.ExcludeTables)
+	for _, t := range s.Tables {
+		if contains(exclude, t.Name) {
+			continue
+		}
+		if contains(nt, t.Name) {
+			continue
+		}
+		name := unqualifiedName(t.Name)
+		if isReserved(name) && !contains(r.Exclude, t.Name) {
+			warns = append(warns, RuleWarn{
+				Target:   t.Name,
+				Message:  fmt.Sprintf(msgFmt, name, dialect),
+				Location: tableLocation(t.Name),
+			})
+		}
+		for _, c := range t.Columns {
+			target := fmt.Sprintf("%s.%s", t.Name, c.Name)
+			if contains(r.Exclude, c.Name) || contains(r.Exclude, target) {
+				continue
+			}
+			if isReserved(c.Name) {
+				warns = append(warns, RuleWarn{
+					Target:   target,
+					Message:  fmt.Sprintf(msgFmt, c.Name, dialect),
+					Location: objectLocation(t.Name, c.Name),
+				})
+			}
+		}
+	}
+	return warns
+}
+
+// namingStyles is styles of names
+var namingStyles = map[string]*regexp.Regexp{
+	"snake_case":       regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`),
+	"UPPER_SNAKE_CASE": regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`),
+	"camelCase":        regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`),
+	"PascalCase":       regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`),
+	"kebab-case":       regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`),
+}
+
+// naming is the compiled style and pattern of a naming rule
+type naming struct {
+	kind    string
+	style   string
+	styleRe *regexp.Regexp
+	pattern string
+	re      *regexp.Regexp
+}
+
+// newNaming return naming of the kind of objects. It return an error if the style is unsupported or the pattern is invalid
+func newNaming(kind, style, pattern string) (*naming, error) {
+	n := &naming{kind: kind, style: style, pattern: pattern}
+	if style != "" {
+		re, ok := namingStyles[style]
+		if !ok {
+			return nil, errors.Errorf("unsupported style '%s'", style)
+		}
+		n.styleRe = re
+	}
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		n.re = re
+	}
+	return n, nil
+}
+
+// check return messages if the name does not follow the style or the pattern
+func (n *naming) check(name string) []string {
+	msgs := []string{}
+	if n.styleRe != nil && !n.styleRe.MatchString(name) {
+		msgs = append(msgs, fmt.Sprintf("%s name should be %s.", n.kind, n.style))
+	}
+	if n.re != nil && !n.re.MatchString(name) {
+		msgs = append(msgs, fmt.Sprintf("%s name should match `%s`.", n.kind, n.pattern))
+	}
+	return msgs
+}
+
+func invalidNamingRule(name string, err error) []RuleWarn {
+	return []RuleWarn{{
+		Target:  name,
+		Message: fmt.Sprintf("invalid naming rule. %s", err),
+	}}
+}
+
+// unqualifiedName return the table name without the schema name
+func unqualifiedName(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+var wordRe = regexp.MustCompile(`[A-Z]*[a-z0-9]+|[A-Z]+`)
+
+// lastWord return the last word of snake_case, camelCase or PascalCase name
+func lastWord(name string) string {
+	words := wordRe.FindAllString(name, -1)
+	if len(words) == 0 {
+		return name
+	}
+	return strings.ToLower(words[len(words)-1])
+}
+
+func isPrimaryKey(c *schema.Constraint) bool {
+	switch strings.ToUpper(c.Type) {
+	case "PRIMARY KEY", "P":
+		return true
+	}
+	return false
+}
+
+func isForeignKey(c *schema.Constraint) bool {
+	switch strings.ToUpper(c.Type) {
+	case schema.TypeFK, "F":
+		return true
+	}
+	return false
+}
+
+// Target kinds of CustomRule
+const (
+	CustomTargetTable      = "table"
+	CustomTargetColumn     = "column"
+	CustomTargetIndex      = "index"
+	CustomTargetRelation   = "relation"
+	CustomTargetConstraint = "constraint"
+)
+
+// CustomRules is the list of user defined rules
+type CustomRules []CustomRule
+
+// CustomRule checks objects with expressions ( https://github.com/antonmedv/expr )
+type CustomRule struct {
+	Enabled  bool     `yaml:"enabled"`
+	Name     string   `yaml:"name"`
+	Target   string   `yaml:"target"`
+	Filter   string   `yaml:"filter"`
+	Assert   string   `yaml:"assert"`
+	Message  string   `yaml:"message"`
+	Severity string   `yaml:"severity"`
+	Exclude  []string `yaml:"exclude"`
+}
+
+// CustomEnv is the environment of expressions and message templates of CustomRule
+type CustomEnv struct {
+	Schema     *schema.Schema
+	Table      *schema.Table
+	Column     *schema.Column
+	Index      *schema.Index
+	Constraint *schema.Constraint
+	Relation   *schema.Relation
+}
+
+// IsEnabled return Rule is enabled or not
+func (r CustomRules) IsEnabled() bool {
+	return len(r) > 0
+}
+
+// Check all custom rules
+func (r CustomRules) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	for _, cr := range r {
+		warns = append(warns, cr.Check(s, exclude)...)
+	}
+	return warns
+}
+
+// IsEnabled return Rule is enabled or not
+func (r CustomRule) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Run all custom rules. It return an error if an expression or a message template fails
+func (r CustomRules) Run(s *schema.Schema, exclude []string) ([]RuleWarn, error) {
+	warns := []RuleWarn{}
+	for _, cr := range r {
+		w, err := cr.Run(s, exclude)
+		if err != nil {
+			return nil, err
+		}
+		warns = append(warns, w...)
+	}
+	return warns, nil
+}
+
+// Validate compile the expressions and the message template and check the target
+func (r CustomRule) Validate() error {
+	if !r.IsEnabled() {
+		return nil
+	}
+	if r.Assert == "" {
+		return errors.Errorf("invalid custom rule '%s'. assert is empty", r.Name)
+	}
+	_, _, _, err := r.compile()
+	return err
+}
+
+func (r CustomRule) compile() (*vm.Program, *vm.Program, *template.Template, error) {
+	switch r.Target {
+	case CustomTargetTable, CustomTargetColumn, CustomTargetIndex, CustomTargetRelation, CustomTargetConstraint:
+	default:
+		return nil, nil, nil, errors.Errorf("invalid custom rule '%s'. unsupported target '%s'", r.Name, r.Target)
+	}
+	filter := "true"
+	if r.Filter != "" {
+		filter = r.Filter
+	}
+	fp, err := expr.Compile(filter, expr.Env(CustomEnv{}), expr.AsBool())
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(err, "invalid filter of custom rule '%s'", r.Name)
+	}
+	ap, err := expr.Compile(r.Assert, expr.Env(CustomEnv{}), expr.AsBool())
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(err, "invalid assert of custom rule '%s'", r.Name)
+	}
+	tmpl, err := template.New(r.Name).Parse(r.Message)
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(err, "invalid message of custom rule '%s'", r.Name)
+	}
+	return fp, ap, tmpl, nil
+}
+
+// Check objects of the target kind that match the filter satisfy the assertion.
+// An error of the rule is returned as a warning ( use Run to get the error )
+func (r CustomRule) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns, err := r.Run(s, exclude)
+	if err != nil {
+		return []RuleWarn{{
+			Target:  r.Name,
+			Message: fmt.Sprintf("invalid custom rule. %s", err),
+		}}
+	}
+	return warns
+}
+
+// Run check objects of the target kind that match the filter satisfy the assertion.
+// It return an error if an expression or the message template fails
+func (r CustomRule) Run(s *schema.Schema, exclude []string) ([]RuleWarn, error) {
+	warns := []RuleWarn{}
+	if !r.IsEnabled() {
+		return warns, nil
+	}
+	fp, ap, tmpl, err := r.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	targets := []string{}
+	envs := []CustomEnv{}
+	locations := []*Location{}
+	add := func(target, name string, env CustomEnv, l *Location) {
+		if contains(r.Exclude, name) || contains(r.Exclude, target) {
+			return
+		}
+		env.Schema = s
+		targets = append(targets, target)
+		envs = append(envs, env)
+		locations = append(locations, l)
+	}
+	nt := s.NormalizeTableNames(r.Exclude)
+	switch r.Target {
+	case CustomTargetRelation:
+		for _, rel := range s.Relations {
+			if contains(exclude, rel.Table.Name) || contains(exclude, rel.ParentTable.Name) {
+				continue
+			}
+			if contains(nt, rel.Table.Name) {
+				continue
+			}
+			cols := []string{}
+			for _, c := range rel.Columns {
+				cols = append(cols, c.Name)
+			}
+			pcols := []string{}
+			for _, c := range rel.ParentColumns {
+				pcols = append(pcols, c.Name)
+			}
+			target := fmt.Sprintf("%s(%s) -> %s(%s)", rel.Table.Name, strings.Join(cols, ", "), rel.ParentTable.Name, strings.Join(pcols, ", "))
+			add(target, target, CustomEnv{Table: rel.Table, Relation: rel}, tableLocation(rel.Table.Name))
+		}
+	case CustomTargetTable, CustomTargetColumn, CustomTargetIndex, CustomTargetConstraint:
+		for _, t := range s.Tables {
+			if contains(exclude, t.Name) {
+				continue
+			}
+			if contains(nt, t.Name) {
+				continue
+			}
+			switch r.Target {
+			case CustomTargetTable:
+				add(t.Name, t.Name, CustomEnv{Table: t}, tableLocation(t.Name))
+			case CustomTargetColumn:
+				for _, c := range t.Columns {
+					add(fmt.Sprintf("%s.%s", t.Name, c.Name), c.Name, CustomEnv{Table: t, Column: c}, objectLocation(t.Name, c.Name))
+				}
+			case CustomTargetIndex:
+				for _, i := range t.Indexes {
+					add(fmt.Sprintf("%s.%s", t.Name, i.Name), i.Name, CustomEnv{Table: t, Index: i}, objectLocation(t.Name, i.Name))
+				}
+			case CustomTargetConstraint:
+				for _, c := range t.Constraints {
+					add(fmt.Sprintf("%s.%s", t.Name, c.Name), c.Name, CustomEnv{Table: t, Constraint: c}, objectLocation(t.Name, c.Name))
+				}
+			}
+		}
+	}
+
+	for i, target := range targets {
+		env := envs[i]
+		matched, err := expr.Run(fp, env)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to run filter of custom rule '%s' on %s", r.Name, target)
+		}
+		if !matched.(bool) {
+			continue
+		}
+		ok, err := expr.Run(ap, env)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to run assert of custom rule '%s' on %s", r.Name, target)
+		}
+		if ok.(bool) {
+			continue
+		}
+		msg := fmt.Sprintf("custom rule '%s' failed. [%s]", r.Name, r.Assert)
+		if r.Message != "" {
+			buf := new(bytes.Buffer)
+			if err := tmpl.Execute(buf, env); err != nil {
+				return nil, errors.Wrapf(err, "failed to execute message of custom rule '%s' on %s", r.Name, target)
+			}
+			msg = buf.String()
+		}
+		warns = append(warns, RuleWarn{
+			Target:   target,
+			Message:  msg,
+			Rule:     r.Name,
+			Severity: r.Severity,
+			Location: locations[i],
+		})
+	}
+	return warns, nil
+}
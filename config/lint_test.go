@@ -3,8 +3,10 @@ package config
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/tmdc-io/tbls/schema"
 )
 
@@ -461,6 +463,437 @@ func TestCheckLabelStyleBigQuery(t *testing.T) {
 	}
 }
 
+func TestRequirePrimaryKey(t *testing.T) {
+	tests := []struct {
+		enabled     bool
+		lintExclude []string
+		exclude     []string
+		want        int
+	}{
+		{true, []string{}, []string{}, 2},
+		{false, []string{}, []string{}, 0},
+		{true, []string{"table_b"}, []string{}, 1},
+		{true, []string{}, []string{"table_*"}, 0},
+	}
+	for i, tt := range tests {
+		r := RequirePrimaryKey{
+			Enabled: tt.enabled,
+			Exclude: tt.exclude,
+		}
+		s := newTestSchema()
+		// table_a has a primary key ( PostgreSQL style type ), table_c is a view
+		s.Tables[0].Constraints = append(s.Tables[0].Constraints, &schema.Constraint{Name: "table_a_pkey", Type: "p", Columns: []string{"column_a1"}})
+		s.Tables[2].Type = "MATERIALIZED VIEW"
+		s.Tables = append(s.Tables, &schema.Table{Name: "table_d", Type: "BASE TABLE", Constraints: []*schema.Constraint{
+			&schema.Constraint{Name: "table_d_unique", Type: "UNIQUE"},
+		}})
+		if warns := r.Check(s, tt.lintExclude); len(warns) != tt.want {
+			t.Errorf("TestRequirePrimaryKey(%d): got %v\nwant %v", i, len(warns), tt.want)
+		}
+	}
+}
+
+func TestForeignKeyTypeMismatch(t *testing.T) {
+	tests := []struct {
+		lintExclude []string
+		exclude     []string
+		want        []RuleWarn
+	}{
+		{[]string{}, []string{}, []RuleWarn{{Target: "posts.user_id", Message: "column type does not match the referenced column. [user_id: int(11), users.id: bigint]", Location: objectLocation("posts", "user_id")}}},
+		{[]string{"users"}, []string{}, []RuleWarn{}},
+		{[]string{}, []string{"posts.user_id"}, []RuleWarn{}},
+	}
+	for i, tt := range tests {
+		r := ForeignKeyTypeMismatch{
+			Enabled: true,
+			Exclude: tt.exclude,
+		}
+		s := newTestTypeSchema()
+		if diff := cmp.Diff(r.Check(s, tt.lintExclude), tt.want, nil); diff != "" {
+			t.Errorf("TestForeignKeyTypeMismatch(%d): %s", i, diff)
+		}
+	}
+}
+
+func TestColumnTypeRules(t *testing.T) {
+	tests := []struct {
+		rule Rule
+		want []RuleWarn
+	}{
+		{VarcharWithoutLength{Enabled: true}, []RuleWarn{{Target: "users.name", Message: "varchar without length.", Location: objectLocation("users", "name")}}},
+		{VarcharWithoutLength{Enabled: true, Exclude: []string{"name"}}, []RuleWarn{}},
+		{VarcharWithoutLength{Enabled: false}, []RuleWarn{}},
+		{TextIdentifier{Enabled: true}, []RuleWarn{{Target: "posts.author_id", Message: "identifier column should not be text.", Location: objectLocation("posts", "author_id")}}},
+		{TextIdentifier{Enabled: true, ExcludeTables: []string{"posts"}}, []RuleWarn{}},
+		{FloatMoney{Enabled: true}, []RuleWarn{
+			{Target: "users.price", Message: "money column should not be floating-point type. [float]", Location: objectLocation("users", "price")},
+			{Target: "posts.total_amount", Message: "money column should not be floating-point type. [double precision]", Location: objectLocation("posts", "total_amount")},
+		}},
+		{FloatMoney{Enabled: true, Columns: []string{"price"}}, []RuleWarn{{Target: "users.price", Message: "money column should not be floating-point type. [float]", Location: objectLocation("users", "price")}}},
+		{FloatMoney{Enabled: true, Columns: []string{"Price", "*_AMOUNT"}}, []RuleWarn{
+			{Target: "users.price", Message: "money column should not be floating-point type. [float]", Location: objectLocation("users", "price")},
+			{Target: "posts.total_amount", Message: "money column should not be floating-point type. [double precision]", Location: objectLocation("posts", "total_amount")},
+		}},
+		{TimestampWithoutTimeZone{Enabled: true}, []RuleWarn{{Target: "users.created", Message: "timestamp without time zone. [timestamp without time zone]", Location: objectLocation("users", "created")}}},
+		{InconsistentColumnType{Enabled: true}, []RuleWarn{
+			{Target: "id", Message: "column has different types across tables. [bigint: users; int: posts]", Location: &Location{Object: "id", Tables: []string{"users", "posts"}}},
+			{Target: "created", Message: "column has different types across tables. [timestamp without time zone: users; timestamp with time zone: posts]", Location: &Location{Object: "created", Tables: []string{"users", "posts"}}},
+		}},
+		{InconsistentColumnType{Enabled: true, Exclude: []string{"created", "posts.id"}}, []RuleWarn{}},
+	}
+	for i, tt := range tests {
+		s := newTestTypeSchema()
+		if diff := cmp.Diff(tt.rule.Check(s, []string{}), tt.want, nil); diff != "" {
+			t.Errorf("TestColumnTypeRules(%d): %s", i, diff)
+		}
+	}
+
+	// timestamp of MySQL is stored in UTC
+	s := newTestTypeSchema()
+	s.Driver.Name = "mysql"
+	s.Tables[0].Columns[3].Type = "timestamp"
+	s.Tables[1].Columns[4].Type = "datetime"
+	want := []RuleWarn{{Target: "posts.created", Message: "timestamp without time zone. [datetime]", Location: objectLocation("posts", "created")}}
+	if diff := cmp.Diff(TimestampWithoutTimeZone{Enabled: true}.Check(s, []string{}), want, nil); diff != "" {
+		t.Errorf("TestColumnTypeRules (mysql): %s", diff)
+	}
+}
+
+func TestIndexRules(t *testing.T) {
+	tests := []struct {
+		rule Rule
+		want []RuleWarn
+	}{
+		{DuplicateIndexes{Enabled: true}, []RuleWarn{
+			{Target: "users.users_id_key", Message: "duplicate index of `users_pkey`. [id]", Location: objectLocation("users", "users_id_key")},
+			{Target: "users.users_name_idx2", Message: "duplicate index of `users_name_idx`. [name]", Location: objectLocation("users", "users_name_idx2")},
+		}},
+		{DuplicateIndexes{Enabled: true, Exclude: []string{"users_id_key"}, ExcludeTables: []string{}}, []RuleWarn{
+			{Target: "users.users_name_idx2", Message: "duplicate index of `users_name_idx`. [name]", Location: objectLocation("users", "users_name_idx2")},
+		}},
+		{DuplicateIndexes{Enabled: false}, []RuleWarn{}},
+		{RedundantIndexes{Enabled: true}, []RuleWarn{
+			{Target: "users.users_name_idx", Message: "index is a left-prefix of `users_name_email_idx`. [name, email]", Location: objectLocation("users", "users_name_idx")},
+			{Target: "users.users_name_idx2", Message: "index is a left-prefix of `users_name_email_idx`. [name, email]", Location: objectLocation("users", "users_name_idx2")},
+		}},
+		{RedundantIndexes{Enabled: true, ExcludeTables: []string{"users"}}, []RuleWarn{}},
+		{IndexCount{Enabled: true, Max: 5}, []RuleWarn{{Target: "users", Message: "too many indexes. [7/5]", Location: tableLocation("users")}}},
+		{IndexCount{Enabled: true, Max: 7}, []RuleWarn{}},
+		{UniqueIndexOnPrimaryKey{Enabled: true}, []RuleWarn{
+			{Target: "users.users_id_key", Message: "unique index duplicates the primary key `users_pkey`. [id]", Location: objectLocation("users", "users_id_key")},
+		}},
+		{UniqueIndexOnPrimaryKey{Enabled: true, Exclude: []string{"users.*_key"}}, []RuleWarn{}},
+	}
+	for i, tt := range tests {
+		s := newTestIndexSchema()
+		if diff := cmp.Diff(tt.rule.Check(s, []string{}), tt.want, nil); diff != "" {
+			t.Errorf("TestIndexRules(%d): %s", i, diff)
+		}
+	}
+}
+
+func TestTableNaming(t *testing.T) {
+	tests := []struct {
+		style       string
+		pattern     string
+		number      string
+		lintExclude []string
+		exclude     []string
+		want        int
+	}{
+		{"", "", "", []string{}, []string{}, 0},
+		{"snake_case", "", "", []string{}, []string{}, 0},
+		{"camelCase", "", "", []string{}, []string{}, 3},
+		{"PascalCase", "", "", []string{"table_a"}, []string{}, 2},
+		{"camelCase", "", "", []string{}, []string{"table_*"}, 0},
+		{"", "^table_[ab]$", "", []string{}, []string{}, 1},
+		{"", "", "singular", []string{}, []string{}, 0},
+		{"", "", "plural", []string{}, []string{}, 3},
+		{"snake_case", "^tbl_", "plural", []string{}, []string{}, 6},
+	}
+	for i, tt := range tests {
+		r := TableNaming{
+			Enabled: true,
+			Style:   tt.style,
+			Pattern: tt.pattern,
+			Number:  tt.number,
+			Exclude: tt.exclude,
+		}
+		s := newTestSchema()
+		if warns := r.Check(s, tt.lintExclude); len(warns) != tt.want {
+			t.Errorf("TestTableNaming(%d): got %v\nwant %v", i, len(warns), tt.want)
+		}
+	}
+
+	for _, r := range []TableNaming{{Enabled: true, Style: "unknown"}, {Enabled: true, Pattern: "["}, {Enabled: true, Number: "dual"}} {
+		warns := r.Check(newTestSchema(), []string{})
+		if len(warns) != 1 || !strings.HasPrefix(warns[0].Message, "invalid naming rule.") {
+			t.Errorf("got %v\nwant invalid naming rule", warns)
+		}
+	}
+}
+
+func TestColumnNaming(t *testing.T) {
+	tests := []struct {
+		style         string
+		pattern       string
+		lintExclude   []string
+		exclude       []string
+		excludeTables []string
+		want          int
+	}{
+		{"snake_case", "", []string{}, []string{}, []string{}, 0},
+		{"camelCase", "", []string{}, []string{}, []string{}, 8},
+		{"camelCase", "", []string{"table_c"}, []string{}, []string{}, 4},
+		{"camelCase", "", []string{}, []string{}, []string{"table_c"}, 4},
+		{"camelCase", "", []string{}, []string{"column_a1", "table_b.*"}, []string{}, 5},
+		{"", "[12]$", []string{}, []string{}, []string{}, 2},
+	}
+	for i, tt := range tests {
+		r := ColumnNaming{
+			Enabled:       true,
+			Style:         tt.style,
+			Pattern:       tt.pattern,
+			Exclude:       tt.exclude,
+			ExcludeTables: tt.excludeTables,
+		}
+		s := newTestSchema()
+		if warns := r.Check(s, tt.lintExclude); len(warns) != tt.want {
+			t.Errorf("TestColumnNaming(%d): got %v\nwant %v", i, len(warns), tt.want)
+		}
+	}
+}
+
+func TestIndexNaming(t *testing.T) {
+	tests := []struct {
+		style   string
+		prefix  string
+		exclude []string
+		want    []RuleWarn
+	}{
+		{"snake_case", "", []string{}, []RuleWarn{}},
+		{"snake_case", "idx_", []string{}, []RuleWarn{{Target: "table_a.a2_idx", Message: "index name should start with `idx_`.", Location: objectLocation("table_a", "a2_idx")}}},
+		{"PascalCase", "", []string{}, []RuleWarn{{Target: "table_a.a2_idx", Message: "index name should be PascalCase.", Location: objectLocation("table_a", "a2_idx")}}},
+		{"PascalCase", "idx_", []string{"table_a.a2_idx"}, []RuleWarn{}},
+	}
+	for i, tt := range tests {
+		r := IndexNaming{
+			Enabled: true,
+			Style:   tt.style,
+			Prefix:  tt.prefix,
+			Exclude: tt.exclude,
+		}
+		s := newTestSchema()
+		s.Tables[0].Indexes = append(s.Tables[0].Indexes, &schema.Index{Name: "a1_unique", Columns: []string{"column_a1"}})
+		if diff := cmp.Diff(r.Check(s, []string{}), tt.want, nil); diff != "" {
+			t.Errorf("TestIndexNaming(%d): %s", i, diff)
+		}
+	}
+}
+
+func TestConstraintNaming(t *testing.T) {
+	tests := []struct {
+		style            string
+		foreignKeyPrefix string
+		exclude          []string
+		want             []RuleWarn
+	}{
+		{"snake_case", "", []string{}, []RuleWarn{}},
+		{"", "fk_", []string{}, []RuleWarn{{Target: "table_a.a1_b1_fk", Message: "foreign key name should start with `fk_`.", Location: objectLocation("table_a", "a1_b1_fk")}}},
+		{"", "fk_", []string{"*_fk"}, []RuleWarn{}},
+		{"UPPER_SNAKE_CASE", "", []string{"a1_b1_fk"}, []RuleWarn{{Target: "table_a.a1_unique", Message: "constraint name should be UPPER_SNAKE_CASE.", Location: objectLocation("table_a", "a1_unique")}}},
+	}
+	for i, tt := range tests {
+		r := ConstraintNaming{
+			Enabled:          true,
+			Style:            tt.style,
+			ForeignKeyPrefix: tt.foreignKeyPrefix,
+			Exclude:          tt.exclude,
+		}
+		s := newTestSchema()
+		if diff := cmp.Diff(r.Check(s, []string{}), tt.want, nil); diff != "" {
+			t.Errorf("TestConstraintNaming(%d): %s", i, diff)
+		}
+	}
+}
+
+func TestReservedWords(t *testing.T) {
+	tests := []struct {
+		driver  string
+		dialect string
+		words   []string
+		exclude []string
+		want    []RuleWarn
+	}{
+		{"postgres", "", []string{}, []string{}, []RuleWarn{
+			{Target: "public.user", Message: "`user` is a reserved word in postgres.", Location: tableLocation("public.user")},
+			{Target: "public.user.order", Message: "`order` is a reserved word in postgres.", Location: objectLocation("public.user", "order")},
+		}},
+		{"mysql", "", []string{}, []string{}, []RuleWarn{
+			{Target: "public.user.order", Message: "`order` is a reserved word in mysql.", Location: objectLocation("public.user", "order")},
+			{Target: "public.user.key", Message: "`key` is a reserved word in mysql.", Location: objectLocation("public.user", "key")},
+		}},
+		{"mysql", "", []string{"name"}, []string{"key"}, []RuleWarn{
+			{Target: "public.user.order", Message: "`order` is a reserved word in mysql.", Location: objectLocation("public.user", "order")},
+			{Target: "public.user.name", Message: "`name` is a reserved word in mysql.", Location: objectLocation("public.user", "name")},
+		}},
+		{"mysql", "postgres", []string{}, []string{"public.user", "public.user.order"}, []RuleWarn{}},
+		{"bigquery", "", []string{}, []string{"order"}, []RuleWarn{
+			{Target: "public.user", Message: "`user` is a reserved word in postgres.", Location: tableLocation("public.user")},
+		}},
+	}
+	for i, tt := range tests {
+		r := ReservedWords{
+			Enabled: true,
+			Dialect: tt.dialect,
+			Words:   tt.words,
+			Exclude: tt.exclude,
+		}
+		s := &schema.Schema{
+			Tables: []*schema.Table{
+				&schema.Table{
+					Name: "public.user",
+					Columns: []*schema.Column{
+						&schema.Column{Name: "id"},
+						&schema.Column{Name: "order"},
+						&schema.Column{Name: "key"},
+						&schema.Column{Name: "name"},
+					},
+				},
+			},
+			Driver: &schema.Driver{Name: tt.driver},
+		}
+		if diff := cmp.Diff(r.Check(s, []string{}), tt.want, nil); diff != "" {
+			t.Errorf("TestReservedWords(%d): %s", i, diff)
+		}
+	}
+}
+
+func TestCustomRule(t *testing.T) {
+	tests := []struct {
+		rule        CustomRule
+		lintExclude []string
+		want        []RuleWarn
+	}{
+		{
+			CustomRule{Enabled: true, Name: "table comment", Target: "table", Assert: "Table.Comment != ''"},
+			[]string{},
+			[]RuleWarn{{Target: "table_a", Message: "custom rule 'table comment' failed. [Table.Comment != '']", Rule: "table comment", Location: tableLocation("table_a")}},
+		},
+		{
+			CustomRule{Enabled: true, Name: "table comment", Target: "table", Assert: "Table.Comment != ''"},
+			[]string{"table_a"},
+			[]RuleWarn{},
+		},
+		{
+			CustomRule{Enabled: true, Name: "not null text", Target: "column", Filter: "Column.Type == 'text'", Assert: "!Column.Nullable", Message: "{{ .Column.Name }} of {{ .Table.Name }} should be NOT NULL."},
+			[]string{},
+			[]RuleWarn{
+				{Target: "table_b.column_b1", Message: "column_b1 of table_b should be NOT NULL.", Rule: "not null text", Location: objectLocation("table_b", "column_b1")},
+				{Target: "table_b.column_b2", Message: "column_b2 of table_b should be NOT NULL.", Rule: "not null text", Location: objectLocation("table_b", "column_b2")},
+			},
+		},
+		{
+			CustomRule{Enabled: true, Name: "not null text", Target: "column", Filter: "Column.Type == 'text'", Assert: "!Column.Nullable", Exclude: []string{"table_b.*_b2"}},
+			[]string{},
+			[]RuleWarn{{Target: "table_b.column_b1", Message: "custom rule 'not null text' failed. [!Column.Nullable]", Rule: "not null text", Location: objectLocation("table_b", "column_b1")}},
+		},
+		{
+			CustomRule{Enabled: true, Name: "index suffix", Target: "index", Assert: "Index.Name endsWith '_index'", Message: "rename {{ .Index.Name }}."},
+			[]string{},
+			[]RuleWarn{{Target: "table_a.a2_idx", Message: "rename a2_idx.", Rule: "index suffix", Location: objectLocation("table_a", "a2_idx")}},
+		},
+		{
+			CustomRule{Enabled: true, Name: "fk suffix", Target: "constraint", Filter: "Constraint.Type == 'FOREIGN KEY'", Assert: "Constraint.Name endsWith '_fk'"},
+			[]string{},
+			[]RuleWarn{},
+		},
+		{
+			CustomRule{Enabled: true, Name: "no self relation", Target: "relation", Assert: "Relation.Table.Name != Relation.ParentTable.Name && len(Relation.Columns) == 1"},
+			[]string{},
+			[]RuleWarn{},
+		},
+		{
+			CustomRule{Enabled: true, Name: "invalid", Target: "table", Assert: "Table.Unknown"},
+			[]string{},
+			nil,
+		},
+		{
+			CustomRule{Enabled: true, Name: "invalid target", Target: "view", Assert: "true"},
+			[]string{},
+			nil,
+		},
+	}
+	for i, tt := range tests {
+		s := newTestSchema()
+		got := tt.rule.Check(s, tt.lintExclude)
+		if tt.want == nil {
+			if len(got) != 1 || !strings.HasPrefix(got[0].Message, "invalid custom rule.") {
+				t.Errorf("TestCustomRule(%d): got %v\nwant invalid custom rule", i, got)
+			}
+			continue
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("TestCustomRule(%d): %s", i, diff)
+		}
+	}
+
+	rules := CustomRules{tests[0].rule, tests[2].rule}
+	if !rules.IsEnabled() {
+		t.Error("got disabled\nwant enabled")
+	}
+	if got := len(rules.Check(newTestSchema(), []string{})); got != 3 {
+		t.Errorf("got %v\nwant %v", got, 3)
+	}
+}
+
+func TestNamingValidate(t *testing.T) {
+	tests := []struct {
+		rule    interface{ Validate() error }
+		wantErr bool
+	}{
+		{TableNaming{Enabled: true, Style: "snake_case", Pattern: "^[a-z_]+$", Number: "plural"}, false},
+		{TableNaming{Enabled: false, Style: "unknown"}, false},
+		{TableNaming{Enabled: true, Style: "unknown"}, true},
+		{TableNaming{Enabled: true, Number: "dual"}, true},
+		{ColumnNaming{Enabled: true, Pattern: "^[a-z"}, true},
+		{IndexNaming{Enabled: true, Style: "Train-Case"}, true},
+		{ConstraintNaming{Enabled: true, Style: "kebab-case", Pattern: "(unclosed"}, true},
+	}
+	for i, tt := range tests {
+		if err := tt.rule.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("TestNamingValidate(%d): got %v\nwantErr %v", i, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCustomRuleValidate(t *testing.T) {
+	tests := []struct {
+		rule    CustomRule
+		wantErr bool
+	}{
+		{CustomRule{Enabled: true, Name: "table comment", Target: "table", Assert: "Table.Comment != ''"}, false},
+		{CustomRule{Name: "disabled", Target: "view"}, false},
+		{CustomRule{Enabled: true, Name: "empty assert", Target: "table"}, true},
+		{CustomRule{Enabled: true, Name: "invalid target", Target: "view", Assert: "true"}, true},
+		{CustomRule{Enabled: true, Name: "invalid filter", Target: "table", Filter: "Table.Unknown", Assert: "true"}, true},
+		{CustomRule{Enabled: true, Name: "invalid assert", Target: "table", Assert: "Table.Name"}, true},
+		{CustomRule{Enabled: true, Name: "invalid message", Target: "table", Assert: "true", Message: "{{ .Table.Name "}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.rule.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: got %v\nwantErr %v", tt.rule.Name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCustomRuleRun(t *testing.T) {
+	r := CustomRule{Enabled: true, Name: "nil column", Target: "table", Assert: "Column.Name != ''"}
+	if _, err := r.Run(newTestSchema(), []string{}); err == nil {
+		t.Error("want error")
+	}
+}
+
 func newTestSchema() *schema.Schema {
 	ca := &schema.Column{
 		Name:     "column_a1",
@@ -646,3 +1079,71 @@ func newTestNoRelationSchema() *schema.Schema {
 	s.Relations = nil
 	return s
 }
+
+func newTestTypeSchema() *schema.Schema {
+	id := &schema.Column{Name: "id", Type: "bigint"}
+	users := &schema.Table{
+		Name: "users",
+		Type: "BASE TABLE",
+		Columns: []*schema.Column{
+			id,
+			&schema.Column{Name: "name", Type: "varchar"},
+			&schema.Column{Name: "price", Type: "float"},
+			&schema.Column{Name: "created", Type: "timestamp without time zone"},
+		},
+	}
+	userID := &schema.Column{Name: "user_id", Type: "int(11)"}
+	posts := &schema.Table{
+		Name: "posts",
+		Type: "BASE TABLE",
+		Columns: []*schema.Column{
+			&schema.Column{Name: "id", Type: "int(11)"},
+			userID,
+			&schema.Column{Name: "author_id", Type: "text"},
+			&schema.Column{Name: "total_amount", Type: "double precision"},
+			&schema.Column{Name: "created", Type: "timestamp with time zone"},
+		},
+	}
+	r := &schema.Relation{
+		Table:         posts,
+		Columns:       []*schema.Column{userID},
+		ParentTable:   users,
+		ParentColumns: []*schema.Column{id},
+	}
+	return &schema.Schema{
+		Name:      "testschema",
+		Tables:    []*schema.Table{users, posts},
+		Relations: []*schema.Relation{r},
+		Driver:    &schema.Driver{Name: "testdriver"},
+	}
+}
+
+func newTestIndexSchema() *schema.Schema {
+	users := &schema.Table{
+		Name: "users",
+		Type: "BASE TABLE",
+		Columns: []*schema.Column{
+			&schema.Column{Name: "id", Type: "bigint"},
+			&schema.Column{Name: "name", Type: "text"},
+			&schema.Column{Name: "email", Type: "text"},
+		},
+		Constraints: []*schema.Constraint{
+			&schema.Constraint{Name: "users_pkey", Type: "PRIMARY KEY", Columns: []string{"id"}},
+			&schema.Constraint{Name: "users_email_key", Type: "UNIQUE", Columns: []string{"email"}},
+		},
+	}
+	users.Indexes = []*schema.Index{
+		&schema.Index{Name: "users_pkey", Def: "CREATE UNIQUE INDEX users_pkey ON users USING btree (id)", Table: &users.Name, Columns: []string{"id"}},
+		&schema.Index{Name: "users_id_key", Def: "CREATE UNIQUE INDEX users_id_key ON users USING btree (id)", Table: &users.Name, Columns: []string{"id"}},
+		&schema.Index{Name: "users_name_idx", Def: "CREATE INDEX users_name_idx ON users USING btree (name)", Table: &users.Name, Columns: []string{"name"}},
+		&schema.Index{Name: "users_name_idx2", Def: "CREATE INDEX users_name_idx2 ON users USING btree (name)", Table: &users.Name, Columns: []string{"name"}},
+		&schema.Index{Name: "users_name_email_idx", Def: "CREATE INDEX users_name_email_idx ON users USING btree (name, email)", Table: &users.Name, Columns: []string{"name", "email"}},
+		&schema.Index{Name: "users_email_key", Def: "CREATE UNIQUE INDEX users_email_key ON users USING btree (email)", Table: &users.Name, Columns: []string{"email"}},
+		&schema.Index{Name: "users_email_name_idx", Def: "CREATE INDEX users_email_name_idx ON users USING btree (email, name)", Table: &users.Name, Columns: []string{"email", "name"}},
+	}
+	return &schema.Schema{
+		Name:   "testschema",
+		Tables: []*schema.Table{users},
+		Driver: &schema.Driver{Name: "testdriver"},
+	}
+}
@@ -1,11 +1,14 @@
 package config
 
+import "reflect"
+
 // Templates holds the configurations to override the default
 // templates used to render the schema and the docs.
 type Templates struct {
 	MD   MD   `yaml:"md,omitempty"`
 	Dot  Dot  `yaml:"dot,omitempty"`
 	PUML PUML `yaml:"puml,omitempty"`
+	D2   D2   `yaml:"d2,omitempty"`
 }
 
 // MD holds the paths to the markdown template files.
@@ -18,8 +21,9 @@ type MD struct {
 // Dot holds the paths to the dot template files.
 // If populated the files are used to override the default ones.
 type Dot struct {
-	Schema string `yaml:"schema,omitempty"`
-	Table  string `yaml:"table,omitempty"`
+	Schema  string `yaml:"schema,omitempty"`
+	Table   string `yaml:"table,omitempty"`
+	Lineage string `yaml:"lineage,omitempty"`
 }
 
 // PUML holds the paths to the PlantUML template files.
@@ -28,3 +32,25 @@ type PUML struct {
 	Schema string `yaml:"schema,omitempty"`
 	Table  string `yaml:"table,omitempty"`
 }
+
+// D2 holds the paths to the D2 template files.
+// If populated the files are used to override the default ones.
+type D2 struct {
+	Schema string `yaml:"schema,omitempty"`
+	Table  string `yaml:"table,omitempty"`
+}
+
+// Paths return the paths of all template files that are set
+func (t Templates) Paths() []string {
+	paths := []string{}
+	v := reflect.ValueOf(t)
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		for j := 0; j < f.NumField(); j++ {
+			if p := f.Field(j).String(); p != "" {
+				paths = append(paths, p)
+			}
+		}
+	}
+	return paths
+}
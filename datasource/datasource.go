@@ -15,6 +15,7 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/dbml"
 	"github.com/tmdc-io/tbls/drivers"
 	"github.com/tmdc-io/tbls/drivers/mariadb"
 	"github.com/tmdc-io/tbls/drivers/mssql"
@@ -23,6 +24,7 @@ import (
 	"github.com/tmdc-io/tbls/drivers/redshift"
 	"github.com/tmdc-io/tbls/drivers/snowflake"
 	"github.com/tmdc-io/tbls/drivers/sqlite"
+	"github.com/tmdc-io/tbls/lineage"
 	"github.com/tmdc-io/tbls/schema"
 	"github.com/xo/dburl"
 )
@@ -39,6 +41,9 @@ func Analyze(dsn config.DSN) (*schema.Schema, error) {
 	if strings.Index(urlstr, "json://") == 0 {
 		return AnalyzeJSON(urlstr)
 	}
+	if strings.Index(urlstr, "dbml://") == 0 {
+		return AnalyzeDBML(urlstr)
+	}
 	if strings.Index(urlstr, "bq://") == 0 || strings.Index(urlstr, "bigquery://") == 0 {
 		return AnalyzeBigquery(urlstr)
 	}
@@ -149,6 +154,7 @@ func Analyze(dsn config.DSN) (*schema.Schema, error) {
 		log.Errorf("Analyze: Failed analyizing : '%s' ",err.Error())
 		return s, err
 	}
+	lineage.Analyze(s)
 	return s, nil
 }
 
@@ -204,6 +210,26 @@ func AnalyzeJSON(urlstr string) (*schema.Schema, error) {
 	return s, nil
 }
 
+// AnalyzeDBML analyze `dbml://`
+func AnalyzeDBML(urlstr string) (*schema.Schema, error) {
+	splitted := strings.Split(urlstr, "dbml://")
+	file, err := os.Open(filepath.Clean(splitted[1]))
+	if err != nil {
+		log.Errorf("AnalyzeDBML: Failed to open : '%s' ",err.Error())
+		return &schema.Schema{}, errors.WithStack(err)
+	}
+	defer file.Close()
+	s, err := dbml.Parse(file)
+	if err != nil {
+		log.Errorf("AnalyzeDBML: Failed parsing : '%s' ",err.Error())
+		return &schema.Schema{}, errors.Wrapf(err, "failed to parse %s", splitted[1])
+	}
+	if s.Name == "" {
+		s.Name = strings.TrimSuffix(filepath.Base(splitted[1]), filepath.Ext(splitted[1]))
+	}
+	return s, nil
+}
+
 // Deprecated
 func AnalyzeJSONString(str string) (*schema.Schema, error) {
 	return AnalyzeJSONStringOrFile(str)
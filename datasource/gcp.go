@@ -11,6 +11,7 @@ import (
 	cloudspanner "cloud.google.com/go/spanner"
 	"github.com/tmdc-io/tbls/drivers/bq"
 	"github.com/tmdc-io/tbls/drivers/spanner"
+	"github.com/tmdc-io/tbls/lineage"
 	"github.com/tmdc-io/tbls/schema"
 )
 
@@ -33,6 +34,7 @@ func AnalyzeBigquery(urlstr string) (*schema.Schema, error) {
 	if err != nil {
 		return s, err
 	}
+	lineage.Analyze(s)
 	return s, nil
 }
 
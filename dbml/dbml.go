@@ -0,0 +1,854 @@
+package dbml
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tmdc-io/tbls/schema"
+)
+
+var nonWordRe = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// Parse parse DBML and return the schema
+func Parse(r io.Reader) (*schema.Schema, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	tokens, err := lex(string(b))
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{
+		tokens:  tokens,
+		aliases: map[string]string{},
+		enums:   map[string][]string{},
+		s: &schema.Schema{
+			Tables:    []*schema.Table{},
+			Relations: []*schema.Relation{},
+			Driver: &schema.Driver{
+				Name: "dbml",
+				Meta: &schema.DriverMeta{},
+			},
+		},
+	}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.s, nil
+}
+
+type parser struct {
+	tokens  []token
+	pos     int
+	s       *schema.Schema
+	aliases map[string]string
+	enums   map[string][]string
+	refs    []*ref
+}
+
+type ref struct {
+	name     string
+	from, to endpoint
+	op       string
+	settings []setting
+	line     int
+}
+
+type endpoint struct {
+	table   string
+	columns []string
+}
+
+type setting struct {
+	key   string
+	value []token
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) skipNewlines() {
+	for p.peek().kind == tokNewline {
+		p.pos++
+	}
+}
+
+func (p *parser) expect(val string) error {
+	t := p.next()
+	if !t.is(tokPunct, val) {
+		return errors.Errorf("line %d: expected '%s' but got '%s'", t.line, val, t.val)
+	}
+	return nil
+}
+
+func (p *parser) parse() error {
+	for {
+		p.skipNewlines()
+		t := p.next()
+		if t.kind == tokEOF {
+			break
+		}
+		if t.kind != tokIdent {
+			return errors.Errorf("line %d: unexpected '%s'", t.line, t.val)
+		}
+		var err error
+		switch strings.ToLower(t.val) {
+		case "project":
+			err = p.parseProject()
+		case "table":
+			err = p.parseTable()
+		case "ref":
+			err = p.parseRef()
+		case "enum":
+			err = p.parseEnum()
+		case "tablegroup":
+			err = p.parseTableGroup()
+		default:
+			// skip unsupported blocks ( e.g. TablePartial, Records )
+			err = p.skipBlock()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return p.resolve()
+}
+
+// parseName parse a ( possibly schema qualified ) name
+func (p *parser) parseName() (string, error) {
+	parts := []string{}
+	for {
+		t := p.next()
+		if t.kind != tokIdent && t.kind != tokQuoted {
+			return "", errors.Errorf("line %d: expected name but got '%s'", t.line, t.val)
+		}
+		parts = append(parts, t.val)
+		if !p.peek().is(tokPunct, ".") {
+			break
+		}
+		p.next()
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// parseSettings parse `[key: value, ...]` if exists
+func (p *parser) parseSettings() ([]setting, error) {
+	settings := []setting{}
+	if !p.peek().is(tokPunct, "[") {
+		return settings, nil
+	}
+	p.next()
+	current := []token{}
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		s := setting{}
+		key := []string{}
+		for i, t := range current {
+			if t.is(tokPunct, ":") {
+				s.value = current[i+1:]
+				break
+			}
+			key = append(key, strings.ToLower(t.val))
+		}
+		s.key = strings.Join(key, " ")
+		settings = append(settings, s)
+		current = []token{}
+	}
+	depth := 0
+	for {
+		t := p.next()
+		switch {
+		case t.kind == tokEOF:
+			return nil, errors.Errorf("line %d: unterminated settings", t.line)
+		case t.kind == tokNewline:
+			continue
+		case t.is(tokPunct, "(") || t.is(tokPunct, "["):
+			depth++
+		case t.is(tokPunct, ")"):
+			depth--
+		case t.is(tokPunct, "]") && depth == 0:
+			flush()
+			return settings, nil
+		case t.is(tokPunct, "]"):
+			depth--
+		case t.is(tokPunct, ",") && depth == 0:
+			flush()
+			continue
+		}
+		current = append(current, t)
+	}
+}
+
+func (p *parser) skipBlock() error {
+	for {
+		t := p.next()
+		switch {
+		case t.kind == tokEOF:
+			return errors.Errorf("line %d: unexpected EOF", t.line)
+		case t.kind == tokNewline:
+			return nil
+		case t.is(tokPunct, "{"):
+			depth := 1
+			for depth > 0 {
+				t := p.next()
+				switch {
+				case t.kind == tokEOF:
+					return errors.Errorf("line %d: unexpected EOF", t.line)
+				case t.is(tokPunct, "{"):
+					depth++
+				case t.is(tokPunct, "}"):
+					depth--
+				}
+			}
+			return nil
+		}
+	}
+}
+
+func (p *parser) parseProject() error {
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+	p.s.Name = name
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for {
+		p.skipNewlines()
+		t := p.next()
+		if t.is(tokPunct, "}") {
+			return nil
+		}
+		if t.kind == tokEOF {
+			return errors.Errorf("line %d: unexpected EOF", t.line)
+		}
+		key := strings.ToLower(t.val)
+		note, err := p.parseNote()
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "note":
+			p.s.Desc = note
+		case "database_type":
+			// the driver name stays dbml so that table names are not normalized as the database type
+			p.s.Driver.DatabaseVersion = note
+		}
+	}
+}
+
+// parseNote parse `: 'value'` or `{ 'value' }` after a key
+func (p *parser) parseNote() (string, error) {
+	t := p.next()
+	switch {
+	case t.is(tokPunct, ":"):
+		v := p.next()
+		return v.val, nil
+	case t.is(tokPunct, "{"):
+		p.skipNewlines()
+		v := p.next()
+		p.skipNewlines()
+		if err := p.expect("}"); err != nil {
+			return "", err
+		}
+		return v.val, nil
+	}
+	return "", errors.Errorf("line %d: unexpected '%s'", t.line, t.val)
+}
+
+func (p *parser) parseTable() error {
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+	if t := p.peek(); t.kind == tokIdent && strings.ToLower(t.val) == "as" {
+		p.next()
+		alias, err := p.parseName()
+		if err != nil {
+			return err
+		}
+		p.aliases[alias] = name
+	}
+	settings, err := p.parseSettings()
+	if err != nil {
+		return err
+	}
+	table := &schema.Table{
+		Name:        name,
+		Type:        "BASE TABLE",
+		Columns:     []*schema.Column{},
+		Indexes:     []*schema.Index{},
+		Constraints: []*schema.Constraint{},
+		Triggers:    []*schema.Trigger{},
+	}
+	for _, s := range settings {
+		if s.key == "note" && len(s.value) > 0 {
+			table.Comment = s.value[0].val
+		}
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	pk := []string{}
+	for {
+		p.skipNewlines()
+		t := p.peek()
+		if t.is(tokPunct, "}") {
+			p.next()
+			break
+		}
+		if t.kind == tokEOF {
+			return errors.Errorf("line %d: unexpected EOF", t.line)
+		}
+		if t.kind == tokIdent {
+			switch strings.ToLower(t.val) {
+			case "note":
+				if n := p.tokens[p.pos+1]; n.is(tokPunct, ":") || n.is(tokPunct, "{") {
+					p.next()
+					note, err := p.parseNote()
+					if err != nil {
+						return err
+					}
+					table.Comment = note
+					continue
+				}
+			case "indexes":
+				if p.tokens[p.pos+1].is(tokPunct, "{") {
+					p.next()
+					p.next()
+					cols, err := p.parseIndexes(table)
+					if err != nil {
+						return err
+					}
+					if len(cols) > 0 {
+						pk = cols
+					}
+					continue
+				}
+			}
+		}
+		cols, err := p.parseColumn(table)
+		if err != nil {
+			return err
+		}
+		pk = append(pk, cols...)
+	}
+	if len(pk) > 0 {
+		addPrimaryKey(table, pk)
+	}
+	p.s.Tables = append(p.s.Tables, table)
+	return nil
+}
+
+// parseColumn parse a column definition and return the column name if it is a primary key
+func (p *parser) parseColumn(table *schema.Table) ([]string, error) {
+	t := p.next()
+	if t.kind != tokIdent && t.kind != tokQuoted {
+		return nil, errors.Errorf("line %d: expected column name but got '%s'", t.line, t.val)
+	}
+	c := &schema.Column{
+		Name:     t.val,
+		Nullable: true,
+	}
+	typ, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	c.Type = typ
+	settings, err := p.parseSettings()
+	if err != nil {
+		return nil, err
+	}
+	pk := []string{}
+	for _, s := range settings {
+		switch s.key {
+		case "pk", "primary key":
+			pk = append(pk, c.Name)
+			c.Nullable = false
+		case "not null":
+			c.Nullable = false
+		case "null":
+			c.Nullable = true
+		case "unique":
+			name := fmt.Sprintf("%s_%s_key", tableBaseName(table.Name), c.Name)
+			table.Constraints = append(table.Constraints, &schema.Constraint{
+				Name:    name,
+				Type:    "UNIQUE",
+				Def:     fmt.Sprintf("UNIQUE (%s)", c.Name),
+				Table:   &table.Name,
+				Columns: []string{c.Name},
+			})
+			table.Indexes = append(table.Indexes, &schema.Index{
+				Name:    name,
+				Def:     fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s (%s)", name, table.Name, c.Name),
+				Table:   &table.Name,
+				Columns: []string{c.Name},
+			})
+		case "increment":
+			c.ExtraDef = "auto_increment"
+		case "note":
+			if len(s.value) > 0 {
+				c.Comment = s.value[0].val
+			}
+		case "default":
+			if len(s.value) > 0 {
+				c.Default = defaultValue(s.value)
+			}
+		case "ref":
+			if len(s.value) < 2 {
+				return nil, errors.Errorf("line %d: invalid ref", t.line)
+			}
+			to, err := parseEndpoint(s.value[1:])
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d", t.line)
+			}
+			p.refs = append(p.refs, &ref{
+				from: endpoint{table: table.Name, columns: []string{c.Name}},
+				to:   to,
+				op:   s.value[0].val,
+				line: t.line,
+			})
+		}
+	}
+	table.Columns = append(table.Columns, c)
+	return pk, nil
+}
+
+// parseType parse a column type ( e.g. varchar(255), "character varying", int[], schema.enum )
+func (p *parser) parseType() (string, error) {
+	t := p.next()
+	if t.kind != tokIdent && t.kind != tokQuoted {
+		return "", errors.Errorf("line %d: expected column type but got '%s'", t.line, t.val)
+	}
+	typ := t.val
+	for p.peek().is(tokPunct, ".") {
+		p.next()
+		typ = fmt.Sprintf("%s.%s", typ, p.next().val)
+	}
+	if p.peek().is(tokPunct, "(") {
+		p.next()
+		args := []string{}
+		for {
+			t := p.next()
+			if t.is(tokPunct, ")") {
+				break
+			}
+			if t.kind == tokEOF || t.kind == tokNewline {
+				return "", errors.Errorf("line %d: unterminated column type", t.line)
+			}
+			if t.is(tokPunct, ",") {
+				continue
+			}
+			args = append(args, t.val)
+		}
+		typ = fmt.Sprintf("%s(%s)", typ, strings.Join(args, ","))
+	}
+	for p.peek().is(tokPunct, "[") && p.tokens[p.pos+1].is(tokPunct, "]") {
+		p.next()
+		p.next()
+		typ = typ + "[]"
+	}
+	return typ, nil
+}
+
+// parseIndexes parse the indexes block and return the columns of the primary key if exists
+func (p *parser) parseIndexes(table *schema.Table) ([]string, error) {
+	pk := []string{}
+	for {
+		p.skipNewlines()
+		t := p.next()
+		if t.is(tokPunct, "}") {
+			return pk, nil
+		}
+		cols := []string{}
+		switch {
+		case t.is(tokPunct, "("):
+			for {
+				c := p.next()
+				if c.is(tokPunct, ")") {
+					break
+				}
+				if c.kind == tokEOF {
+					return nil, errors.Errorf("line %d: unexpected EOF", c.line)
+				}
+				if c.is(tokPunct, ",") || c.kind == tokNewline {
+					continue
+				}
+				cols = append(cols, c.val)
+			}
+		case t.kind == tokIdent || t.kind == tokQuoted || t.kind == tokExpr:
+			cols = append(cols, t.val)
+		default:
+			return nil, errors.Errorf("line %d: unexpected '%s'", t.line, t.val)
+		}
+		settings, err := p.parseSettings()
+		if err != nil {
+			return nil, err
+		}
+		name := ""
+		unique := false
+		primary := false
+		using := ""
+		comment := ""
+		for _, s := range settings {
+			switch s.key {
+			case "pk", "primary key":
+				primary = true
+			case "unique":
+				unique = true
+			case "name":
+				if len(s.value) > 0 {
+					name = s.value[0].val
+				}
+			case "type":
+				if len(s.value) > 0 {
+					using = s.value[0].val
+				}
+			case "note":
+				if len(s.value) > 0 {
+					comment = s.value[0].val
+				}
+			}
+		}
+		if primary {
+			pk = cols
+			continue
+		}
+		if name == "" {
+			name = fmt.Sprintf("%s_%s_idx", tableBaseName(table.Name), strings.Trim(nonWordRe.ReplaceAllString(strings.Join(cols, "_"), "_"), "_"))
+		}
+		def := "CREATE INDEX"
+		if unique {
+			def = "CREATE UNIQUE INDEX"
+		}
+		def = fmt.Sprintf("%s %s ON %s", def, name, table.Name)
+		if using != "" {
+			def = fmt.Sprintf("%s USING %s", def, using)
+		}
+		table.Indexes = append(table.Indexes, &schema.Index{
+			Name:    name,
+			Def:     fmt.Sprintf("%s (%s)", def, strings.Join(cols, ", ")),
+			Table:   &table.Name,
+			Columns: cols,
+			Comment: comment,
+		})
+	}
+}
+
+func (p *parser) parseRef() error {
+	name := ""
+	if t := p.peek(); t.kind == tokIdent || t.kind == tokQuoted {
+		name = p.next().val
+	}
+	block := false
+	t := p.next()
+	switch {
+	case t.is(tokPunct, ":"):
+	case t.is(tokPunct, "{"):
+		block = true
+		p.skipNewlines()
+	default:
+		return errors.Errorf("line %d: unexpected '%s'", t.line, t.val)
+	}
+	line := p.peek().line
+	tokens := []token{}
+	for {
+		t := p.peek()
+		if t.kind == tokNewline || t.kind == tokEOF || t.is(tokPunct, "[") || t.is(tokPunct, "}") {
+			break
+		}
+		tokens = append(tokens, p.next())
+	}
+	settings, err := p.parseSettings()
+	if err != nil {
+		return err
+	}
+	if block {
+		p.skipNewlines()
+		if err := p.expect("}"); err != nil {
+			return err
+		}
+	}
+	opIdx := -1
+	for i, t := range tokens {
+		if t.kind == tokPunct && (t.val == ">" || t.val == "<" || t.val == "-" || t.val == "<>") {
+			opIdx = i
+			break
+		}
+	}
+	if opIdx < 0 {
+		return errors.Errorf("line %d: invalid ref", line)
+	}
+	from, err := parseEndpoint(tokens[:opIdx])
+	if err != nil {
+		return errors.Wrapf(err, "line %d", line)
+	}
+	to, err := parseEndpoint(tokens[opIdx+1:])
+	if err != nil {
+		return errors.Wrapf(err, "line %d", line)
+	}
+	p.refs = append(p.refs, &ref{
+		name:     name,
+		from:     from,
+		to:       to,
+		op:       tokens[opIdx].val,
+		settings: settings,
+		line:     line,
+	})
+	return nil
+}
+
+// parseEndpoint parse `table.column` or `schema.table.(column, ...)`
+func parseEndpoint(tokens []token) (endpoint, error) {
+	e := endpoint{}
+	parts := []string{}
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		switch {
+		case t.is(tokPunct, "."):
+		case t.is(tokPunct, "("):
+			for i++; i < len(tokens) && !tokens[i].is(tokPunct, ")"); i++ {
+				if tokens[i].kind == tokIdent || tokens[i].kind == tokQuoted {
+					e.columns = append(e.columns, tokens[i].val)
+				}
+			}
+		case t.kind == tokIdent || t.kind == tokQuoted:
+			parts = append(parts, t.val)
+		default:
+			return e, errors.Errorf("invalid ref endpoint '%s'", t.val)
+		}
+	}
+	if len(e.columns) == 0 {
+		if len(parts) < 2 {
+			return e, errors.New("invalid ref endpoint")
+		}
+		e.columns = []string{parts[len(parts)-1]}
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) == 0 {
+		return e, errors.New("invalid ref endpoint")
+	}
+	e.table = strings.Join(parts, ".")
+	return e, nil
+}
+
+func (p *parser) parseEnum() error {
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	values := []string{}
+	for {
+		p.skipNewlines()
+		t := p.next()
+		if t.is(tokPunct, "}") {
+			break
+		}
+		if t.kind != tokIdent && t.kind != tokQuoted {
+			return errors.Errorf("line %d: unexpected '%s'", t.line, t.val)
+		}
+		values = append(values, t.val)
+		if _, err := p.parseSettings(); err != nil {
+			return err
+		}
+	}
+	p.enums[name] = values
+	return nil
+}
+
+func (p *parser) parseTableGroup() error {
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for {
+		p.skipNewlines()
+		if p.peek().is(tokPunct, "}") {
+			p.next()
+			return nil
+		}
+		tn, err := p.parseName()
+		if err != nil {
+			return err
+		}
+		t, err := p.findTable(tn)
+		if err != nil {
+			return err
+		}
+		if !hasLabel(t, name) {
+			t.Labels = append(t.Labels, &schema.Label{Name: name})
+		}
+	}
+}
+
+func (p *parser) findTable(name string) (*schema.Table, error) {
+	if n, ok := p.aliases[name]; ok {
+		name = n
+	}
+	return p.s.FindTableByName(name)
+}
+
+// resolve build relations from refs and replace enum types with enum values
+func (p *parser) resolve() error {
+	mysql := strings.Contains(strings.ToLower(p.s.Driver.DatabaseVersion), "mysql") ||
+		strings.Contains(strings.ToLower(p.s.Driver.DatabaseVersion), "maria")
+	for _, t := range p.s.Tables {
+		for _, c := range t.Columns {
+			values, ok := p.enums[c.Type]
+			if !ok || !mysql {
+				continue
+			}
+			quoted := []string{}
+			for _, v := range values {
+				quoted = append(quoted, fmt.Sprintf("'%s'", strings.ReplaceAll(v, "'", "''")))
+			}
+			c.Type = fmt.Sprintf("enum(%s)", strings.Join(quoted, ","))
+		}
+	}
+
+	for _, rf := range p.refs {
+		child, parent := rf.from, rf.to
+		if rf.op == "<" {
+			child, parent = parent, child
+		}
+		ct, err := p.findTable(child.table)
+		if err != nil {
+			return errors.Wrapf(err, "line %d", rf.line)
+		}
+		pt, err := p.findTable(parent.table)
+		if err != nil {
+			return errors.Wrapf(err, "line %d", rf.line)
+		}
+		if len(child.columns) != len(parent.columns) {
+			return errors.Errorf("line %d: the number of columns of ref does not match", rf.line)
+		}
+		r := &schema.Relation{
+			Table:       ct,
+			ParentTable: pt,
+		}
+		for i := range child.columns {
+			cc, err := ct.FindColumnByName(child.columns[i])
+			if err != nil {
+				return errors.Wrapf(err, "line %d", rf.line)
+			}
+			pc, err := pt.FindColumnByName(parent.columns[i])
+			if err != nil {
+				return errors.Wrapf(err, "line %d", rf.line)
+			}
+			r.Columns = append(r.Columns, cc)
+			r.ParentColumns = append(r.ParentColumns, pc)
+			cc.ParentRelations = append(cc.ParentRelations, r)
+			pc.ChildRelations = append(pc.ChildRelations, r)
+		}
+		r.Def = fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)", strings.Join(child.columns, ", "), pt.Name, strings.Join(parent.columns, ", "))
+		for _, s := range rf.settings {
+			if (s.key == "delete" || s.key == "update") && len(s.value) > 0 {
+				action := []string{}
+				for _, v := range s.value {
+					action = append(action, strings.ToUpper(v.val))
+				}
+				r.Def = fmt.Sprintf("%s ON %s %s", r.Def, strings.ToUpper(s.key), strings.Join(action, " "))
+			}
+		}
+		switch rf.op {
+		case "-":
+			r.Cardinality = schema.ZeroOrOne
+		case "<>":
+			r.Cardinality = schema.ZeroOrMore
+			r.ParentCardinality = schema.ZeroOrMore
+		}
+		r.SetDefaultCardinality()
+
+		name := rf.name
+		if name == "" {
+			name = fmt.Sprintf("%s_%s_fkey", tableBaseName(ct.Name), strings.Join(child.columns, "_"))
+		}
+		ct.Constraints = append(ct.Constraints, &schema.Constraint{
+			Name:              name,
+			Type:              schema.TypeFK,
+			Def:               r.Def,
+			Table:             &ct.Name,
+			ReferencedTable:   &pt.Name,
+			Columns:           child.columns,
+			ReferencedColumns: parent.columns,
+		})
+		p.s.Relations = append(p.s.Relations, r)
+	}
+	return nil
+}
+
+func hasLabel(t *schema.Table, name string) bool {
+	for _, l := range t.Labels {
+		if l.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func addPrimaryKey(table *schema.Table, columns []string) {
+	name := fmt.Sprintf("%s_pkey", tableBaseName(table.Name))
+	def := fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(columns, ", "))
+	table.Constraints = append([]*schema.Constraint{&schema.Constraint{
+		Name:    name,
+		Type:    "PRIMARY KEY",
+		Def:     def,
+		Table:   &table.Name,
+		Columns: columns,
+	}}, table.Constraints...)
+	table.Indexes = append([]*schema.Index{&schema.Index{
+		Name:    name,
+		Def:     def,
+		Table:   &table.Name,
+		Columns: columns,
+	}}, table.Indexes...)
+	for _, n := range columns {
+		if c, err := table.FindColumnByName(n); err == nil {
+			c.Nullable = false
+		}
+	}
+}
+
+func defaultValue(tokens []token) sql.NullString {
+	t := tokens[0]
+	switch t.kind {
+	case tokString:
+		return sql.NullString{String: fmt.Sprintf("'%s'", strings.ReplaceAll(t.val, "'", "''")), Valid: true}
+	case tokIdent:
+		if strings.ToLower(t.val) == "null" {
+			return sql.NullString{}
+		}
+	}
+	return sql.NullString{String: t.val, Valid: true}
+}
+
+// tableBaseName return the table name without the schema part
+func tableBaseName(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
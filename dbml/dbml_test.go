@@ -0,0 +1,166 @@
+package dbml
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/output/dbml"
+	"github.com/tmdc-io/tbls/schema"
+)
+
+func TestParse(t *testing.T) {
+	s := parseFile(t, filepath.Join(testdataDir(), "dbml_test.dbml"))
+
+	if got, want := s.Name, "blog"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := len(s.Tables), 3; got != want {
+		t.Fatalf("got %v\nwant %v", got, want)
+	}
+	users, err := s.FindTableByName("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := users.Comment, "Users of the blog.\nIncludes deactivated users."; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	email, err := users.FindColumnByName("email")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := email.Type, "character varying(255)"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if email.Nullable {
+		t.Error("got nullable\nwant not null")
+	}
+	created, err := users.FindColumnByName("created")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := created.Default.String, "now()"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+
+	comments, err := s.FindTableByName("comments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := comments.Constraints[0].Def, "PRIMARY KEY (post_id, seq)"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := len(comments.Labels), 1; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+
+	if got, want := len(s.Relations), 3; got != want {
+		t.Fatalf("got %v\nwant %v", got, want)
+	}
+	for _, r := range s.Relations {
+		if r.Table.Name == r.ParentTable.Name {
+			t.Errorf("got self relation %s", r.Def)
+		}
+	}
+	if got, want := s.Relations[2].Table.Name, "comments"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := s.Relations[1].Def, "FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func TestParseEnum(t *testing.T) {
+	in := `Project shop {
+  database_type: 'MySQL'
+}
+
+Table orders {
+  status order_status [not null]
+}
+
+Enum order_status {
+  pending
+  "shipped"
+}
+`
+	s, err := Parse(bytes.NewBufferString(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := s.Tables[0].Columns[0].Type, "enum('pending','shipped')"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	c, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.LoadConfigFile(filepath.Join(testdataDir(), "out_test_tbls.yml")); err != nil {
+		t.Fatal(err)
+	}
+	s := parseFile(t, filepath.Join(testdataDir(), "dbml_test.dbml"))
+	o := dbml.New(c)
+	buf := &bytes.Buffer{}
+	if err := o.OutputSchema(buf, s); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := os.ReadFile(filepath.Join(testdataDir(), "dbml_test.dbml.golden"))
+	got := buf.String()
+	if got != string(want) {
+		t.Errorf("got %v\nwant %v", got, string(want))
+	}
+
+	// parsing the output produces the same DBML
+	s2, err := Parse(bytes.NewBufferString(got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf2 := &bytes.Buffer{}
+	if err := o.OutputSchema(buf2, s2); err != nil {
+		t.Fatal(err)
+	}
+	if buf2.String() != got {
+		t.Errorf("got %v\nwant %v", buf2.String(), got)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		in string
+	}{
+		{"Table users {\n  id integer\n"},
+		{"Table users {\n  id integer [pk\n}\n"},
+		{"Table users {\n  id integer\n}\nRef: users.id > posts.id\n"},
+		{"Table users {\n  name 'varchar'\n}\n"},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(bytes.NewBufferString(tt.in)); err == nil {
+			t.Errorf("%q: got nil\nwant error", tt.in)
+		}
+	}
+}
+
+func parseFile(t *testing.T, path string) *schema.Schema {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	s, err := Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func testdataDir() string {
+	wd, _ := os.Getwd()
+	dir, _ := filepath.Abs(filepath.Join(filepath.Dir(wd), "testdata"))
+	return dir
+}
@@ -0,0 +1,172 @@
+package dbml
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNewline
+	tokIdent  // name, keyword, number or color
+	tokQuoted // "double quoted name"
+	tokString // 'single quoted' or '''triple quoted''' string
+	tokExpr   // `backtick expression`
+	tokPunct  // { } [ ] ( ) , : . > < - <>
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+	line int
+}
+
+func (t token) is(kind tokenKind, val string) bool {
+	return t.kind == kind && t.val == val
+}
+
+// lex split DBML source into tokens
+func lex(src string) ([]token, error) {
+	tokens := []token{}
+	rs := []rune(src)
+	line := 1
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case r == '\n':
+			tokens = append(tokens, token{kind: tokNewline, line: line})
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '/':
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '*':
+			i += 2
+			for i < len(rs) && !(rs[i] == '*' && i+1 < len(rs) && rs[i+1] == '/') {
+				if rs[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			if i >= len(rs) {
+				return nil, errors.Errorf("line %d: unterminated comment", line)
+			}
+			i += 2
+		case r == '\'' && strings.HasPrefix(string(rs[i:]), "'''"):
+			start := line
+			i += 3
+			sb := strings.Builder{}
+			for ; ; i++ {
+				if i >= len(rs) {
+					return nil, errors.Errorf("line %d: unterminated string", start)
+				}
+				if rs[i] == '\\' && i+1 < len(rs) {
+					i++
+					sb.WriteRune(rs[i])
+					continue
+				}
+				if strings.HasPrefix(string(rs[i:]), "'''") {
+					i += 3
+					break
+				}
+				if rs[i] == '\n' {
+					line++
+				}
+				sb.WriteRune(rs[i])
+			}
+			tokens = append(tokens, token{kind: tokString, val: trimIndent(sb.String()), line: start})
+		case r == '\'' || r == '"' || r == '`':
+			kind := map[rune]tokenKind{'\'': tokString, '"': tokQuoted, '`': tokExpr}[r]
+			i++
+			sb := strings.Builder{}
+			for ; ; i++ {
+				if i >= len(rs) || rs[i] == '\n' {
+					return nil, errors.Errorf("line %d: unterminated %c", line, r)
+				}
+				if rs[i] == '\\' && i+1 < len(rs) && kind != tokExpr {
+					i++
+					switch rs[i] {
+					case 'n':
+						sb.WriteRune('\n')
+					case 't':
+						sb.WriteRune('\t')
+					default:
+						sb.WriteRune(rs[i])
+					}
+					continue
+				}
+				if rs[i] == r {
+					i++
+					break
+				}
+				sb.WriteRune(rs[i])
+			}
+			tokens = append(tokens, token{kind: kind, val: sb.String(), line: line})
+		case r == '<' && i+1 < len(rs) && rs[i+1] == '>':
+			tokens = append(tokens, token{kind: tokPunct, val: "<>", line: line})
+			i += 2
+		case r == '-' && i+1 < len(rs) && unicode.IsDigit(rs[i+1]):
+			j := i + 1
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokIdent, val: string(rs[i:j]), line: line})
+			i = j
+		case strings.ContainsRune("{}[](),:.><-", r):
+			tokens = append(tokens, token{kind: tokPunct, val: string(r), line: line})
+			i++
+		case isIdentRune(r) || r == '#':
+			j := i + 1
+			for j < len(rs) && (isIdentRune(rs[j]) || (unicode.IsDigit(r) && rs[j] == '.')) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokIdent, val: string(rs[i:j]), line: line})
+			i = j
+		default:
+			return nil, errors.Errorf("line %d: unexpected character %q", line, r)
+		}
+	}
+	tokens = append(tokens, token{kind: tokEOF, line: line})
+	return tokens, nil
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// trimIndent remove the common indentation of a multi-line string
+func trimIndent(s string) string {
+	lines := strings.Split(s, "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	indent := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		n := len(l) - len(strings.TrimLeft(l, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	if indent <= 0 {
+		return strings.Join(lines, "\n")
+	}
+	for i, l := range lines {
+		if len(l) >= indent {
+			lines[i] = l[indent:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
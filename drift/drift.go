@@ -0,0 +1,221 @@
+package drift
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/tmdc-io/tbls/schema"
+)
+
+// Kinds of object
+const (
+	KindTable      = "table"
+	KindColumn     = "column"
+	KindIndex      = "index"
+	KindConstraint = "constraint"
+)
+
+// Missing is the mark of an object that does not exist in the environment
+const Missing = "-"
+
+// Env is an environment to compare
+type Env struct {
+	Name   string
+	Schema *schema.Schema
+}
+
+// Report is the result of drift detection
+type Report struct {
+	Envs    []string  `json:"envs"`
+	Objects []*Object `json:"objects"`
+}
+
+// Object is an object that differs between environments
+type Object struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Values is the definition of the object per environment ( nil when the object does not exist )
+	Values map[string]*string `json:"values"`
+	// Variants is the mark of the definition per environment. The same definitions have the same mark ( A, B, ... )
+	Variants map[string]string `json:"variants"`
+}
+
+type key struct {
+	kind  string
+	name  string
+	table string
+}
+
+// Detect compare schemas of environments object by object and return objects that differ.
+// Objects of a table that is missing in some environments are reported only as the missing table,
+// unless they also differ between the environments that have the table
+func Detect(envs []*Env) (*Report, error) {
+	if len(envs) < 2 {
+		return nil, errors.New("drift detection requires at least 2 environments")
+	}
+	r := &Report{
+		Envs:    []string{},
+		Objects: []*Object{},
+	}
+	keys := []key{}
+	defs := map[key]map[string]*string{}
+	for _, e := range envs {
+		for _, n := range r.Envs {
+			if n == e.Name {
+				return nil, errors.Errorf("duplicate environment '%s'", e.Name)
+			}
+		}
+		r.Envs = append(r.Envs, e.Name)
+		for _, o := range objects(e.Schema) {
+			k := key{o.kind, o.name, o.table}
+			if _, ok := defs[k]; !ok {
+				keys = append(keys, k)
+				defs[k] = map[string]*string{}
+			}
+			def := o.def
+			defs[k][e.Name] = &def
+		}
+	}
+	for _, k := range keys {
+		values := defs[k]
+		variants := map[string]string{}
+		marks := map[string]string{}
+		for _, n := range r.Envs {
+			v, ok := values[n]
+			if !ok {
+				values[n] = nil
+				variants[n] = Missing
+				continue
+			}
+			if _, ok := marks[*v]; !ok {
+				marks[*v] = string(rune('A' + len(marks)))
+			}
+			variants[n] = marks[*v]
+		}
+		if len(marks) == 1 && !contains(variants, Missing) {
+			continue
+		}
+		if k.kind != KindTable && len(marks) == 1 && missingWithTable(variants, defs[key{KindTable, k.table, k.table}]) {
+			continue
+		}
+		r.Objects = append(r.Objects, &Object{
+			Kind:     k.kind,
+			Name:     k.name,
+			Values:   values,
+			Variants: variants,
+		})
+	}
+	return r, nil
+}
+
+// Drifted return true if any object differs
+func (r *Report) Drifted() bool {
+	return len(r.Objects) > 0
+}
+
+// Write write the report as a matrix of objects and environments, followed by the definition of each variant
+// and the environments that have it
+func (r *Report) Write(wr io.Writer) error {
+	if !r.Drifted() {
+		if _, err := fmt.Fprintf(wr, "no drift between %s\n", strings.Join(r.Envs, ", ")); err != nil {
+			return errors.WithStack(err)
+		}
+		return nil
+	}
+	tw := tabwriter.NewWriter(wr, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "KIND\tNAME\t%s\n", strings.Join(r.Envs, "\t"))
+	for _, o := range r.Objects {
+		cells := []string{}
+		for _, n := range r.Envs {
+			cells = append(cells, o.Variants[n])
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", o.Kind, o.Name, strings.Join(cells, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return errors.WithStack(err)
+	}
+	for _, o := range r.Objects {
+		_, _ = fmt.Fprintf(tw, "\n%s %s\n", o.Kind, o.Name)
+		marks := []string{}
+		envs := map[string][]string{}
+		defs := map[string]string{}
+		for _, n := range r.Envs {
+			m := o.Variants[n]
+			if _, ok := envs[m]; !ok && m != Missing {
+				marks = append(marks, m)
+			}
+			envs[m] = append(envs[m], n)
+			if v := o.Values[n]; v != nil {
+				defs[m] = *v
+			}
+		}
+		if _, ok := envs[Missing]; ok {
+			marks = append(marks, Missing)
+		}
+		for _, m := range marks {
+			def := defs[m]
+			if m == Missing {
+				def = "( not exist )"
+			}
+			_, _ = fmt.Fprintf(tw, "  %s\t%s\t%s\n", m, strings.Join(envs[m], ", "), def)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+type object struct {
+	kind  string
+	name  string
+	table string
+	def   string
+}
+
+// objects return comparable objects of the schema
+func objects(s *schema.Schema) []object {
+	objs := []object{}
+	for _, t := range s.Tables {
+		objs = append(objs, object{KindTable, t.Name, t.Name, t.Type})
+		for _, c := range t.Columns {
+			def := c.Type
+			if !c.Nullable {
+				def += " NOT NULL"
+			}
+			if c.Default.Valid {
+				def += fmt.Sprintf(" DEFAULT %s", c.Default.String)
+			}
+			objs = append(objs, object{KindColumn, fmt.Sprintf("%s.%s", t.Name, c.Name), t.Name, def})
+		}
+		for _, i := range t.Indexes {
+			objs = append(objs, object{KindIndex, fmt.Sprintf("%s.%s", t.Name, i.Name), t.Name, i.Def})
+		}
+		for _, c := range t.Constraints {
+			objs = append(objs, object{KindConstraint, fmt.Sprintf("%s.%s", t.Name, c.Name), t.Name, c.Def})
+		}
+	}
+	return objs
+}
+
+// missingWithTable return true if the object is missing only in environments where its table is missing
+func missingWithTable(variants map[string]string, tableValues map[string]*string) bool {
+	for n, v := range variants {
+		if (tableValues[n] == nil) != (v == Missing) {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(m map[string]string, v string) bool {
+	for _, mv := range m {
+		if mv == v {
+			return true
+		}
+	}
+	return false
+}
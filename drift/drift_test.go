@@ -0,0 +1,108 @@
+package drift
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/datasource"
+	"github.com/tmdc-io/tbls/schema"
+)
+
+func TestDetect(t *testing.T) {
+	staging := testSchema(t)
+	prod := testSchema(t)
+	dev := testSchema(t)
+
+	// prod lacks public.logs
+	tables := []*schema.Table{}
+	for _, tbl := range prod.Tables {
+		if tbl.Name != "public.logs" {
+			tables = append(tables, tbl)
+		}
+	}
+	prod.Tables = tables
+
+	// dev has a different type of public.posts.title and a new index
+	posts, err := dev.FindTableByName("public.posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	title, err := posts.FindColumnByName("title")
+	if err != nil {
+		t.Fatal(err)
+	}
+	title.Type = "text"
+	posts.Indexes = append(posts.Indexes, &schema.Index{Name: "posts_title_idx", Def: "CREATE INDEX posts_title_idx ON public.posts USING btree (title)"})
+
+	// dev also has a different type of public.logs.payload ( public.logs is missing in prod )
+	logs, err := dev.FindTableByName("public.logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := logs.FindColumnByName("payload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload.Type = "jsonb"
+
+	r, err := Detect([]*Env{{"staging", staging}, {"prod", prod}, {"dev", dev}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, o := range r.Objects {
+		got = append(got, o.Kind+" "+o.Name+" "+o.Variants["staging"]+o.Variants["prod"]+o.Variants["dev"])
+	}
+	want := []string{
+		"column public.posts.title AAB",
+		"table public.logs A-A",
+		"column public.logs.payload A-B",
+		"index public.posts.posts_title_idx --A",
+	}
+	if diff := cmp.Diff(got, want, nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+	if r.Objects[0].Values["prod"] == nil || r.Objects[3].Values["prod"] != nil {
+		t.Errorf("unexpected values: %v", r.Objects[3].Values)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := r.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	wantHeader := "KIND    NAME                          staging  prod  dev\n"
+	if !bytes.HasPrefix(buf.Bytes(), []byte(wantHeader)) {
+		t.Errorf("got %v\nwant %v", buf.String(), wantHeader)
+	}
+	wantDetail := "column public.logs.payload\n  A  staging  text\n  B  dev      jsonb\n  -  prod     ( not exist )\n"
+	if !bytes.Contains(buf.Bytes(), []byte(wantDetail)) {
+		t.Errorf("got %v\nwant %v", buf.String(), wantDetail)
+	}
+}
+
+func TestNoDrift(t *testing.T) {
+	r, err := Detect([]*Env{{"a", testSchema(t)}, {"b", testSchema(t)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Drifted() {
+		t.Errorf("got %v\nwant no drift", r.Objects)
+	}
+	if _, err := Detect([]*Env{{"a", testSchema(t)}}); err == nil {
+		t.Error("want error")
+	}
+	if _, err := Detect([]*Env{{"a", testSchema(t)}, {"a", testSchema(t)}}); err == nil {
+		t.Error("want error")
+	}
+}
+
+func testSchema(t *testing.T) *schema.Schema {
+	t.Helper()
+	s, err := datasource.Analyze(config.DSN{URL: "json://../testdata/testdb.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
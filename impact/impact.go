@@ -0,0 +1,184 @@
+package impact
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tmdc-io/tbls/schema"
+)
+
+// Kinds of dependency
+const (
+	KindForeignKey      = "foreign key"
+	KindVirtualRelation = "virtual relation"
+	KindView            = "view"
+)
+
+// Impact is the result of impact analysis of a table or a column
+type Impact struct {
+	Table      string       `json:"table"`
+	Column     string       `json:"column,omitempty"`
+	Dependents []*Dependent `json:"dependents"`
+}
+
+// Dependent is an object that depends on the target
+type Dependent struct {
+	Table      string       `json:"table"`
+	Columns    []string     `json:"columns,omitempty"`
+	Kind       string       `json:"kind"`
+	Def        string       `json:"def,omitempty"`
+	Depth      int          `json:"depth"`
+	Dependents []*Dependent `json:"dependents,omitempty"`
+}
+
+// FindTarget find table and column by `table` or `table.column`
+func FindTarget(s *schema.Schema, target string) (*schema.Table, *schema.Column, error) {
+	if t, err := s.FindTableByName(target); err == nil {
+		return t, nil, nil
+	}
+	i := strings.LastIndex(target, ".")
+	if i < 0 {
+		return nil, nil, errors.Errorf("not found table '%s'", target)
+	}
+	t, err := s.FindTableByName(target[:i])
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	c, err := t.FindColumnByName(target[i+1:])
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	return t, c, nil
+}
+
+// Analyze list objects that depend on the table ( or the column when c is not nil ) up to depth. depth 0 means unlimited.
+func Analyze(s *schema.Schema, t *schema.Table, c *schema.Column, depth int) *Impact {
+	w := &walker{
+		schema:  s,
+		depth:   depth,
+		visited: map[string]struct{}{},
+	}
+	i := &Impact{
+		Table:      t.Name,
+		Dependents: []*Dependent{},
+	}
+	if c == nil {
+		w.visited[t.Name] = struct{}{}
+		i.Dependents = append(i.Dependents, w.table(t, 1)...)
+		return i
+	}
+	i.Column = c.Name
+	w.visited[fmt.Sprintf("%s.%s", t.Name, c.Name)] = struct{}{}
+	i.Dependents = append(i.Dependents, w.column(t, c, 1)...)
+	return i
+}
+
+type walker struct {
+	schema  *schema.Schema
+	depth   int
+	visited map[string]struct{}
+}
+
+func (w *walker) table(t *schema.Table, d int) []*Dependent {
+	if w.depth > 0 && d > w.depth {
+		return nil
+	}
+	var dependents []*Dependent
+	encountered := map[*schema.Relation]struct{}{}
+	for _, c := range t.Columns {
+		for _, r := range c.ChildRelations {
+			if _, ok := encountered[r]; ok {
+				continue
+			}
+			encountered[r] = struct{}{}
+			dependents = append(dependents, w.relation(r, d))
+		}
+	}
+	for _, v := range w.schema.ReferencedBy(t) {
+		dependents = append(dependents, w.view(v, d))
+	}
+	return dependents
+}
+
+func (w *walker) column(t *schema.Table, c *schema.Column, d int) []*Dependent {
+	if w.depth > 0 && d > w.depth {
+		return nil
+	}
+	var dependents []*Dependent
+	for _, r := range c.ChildRelations {
+		dependents = append(dependents, w.relation(r, d))
+	}
+	for _, v := range w.schema.ReferencedBy(t) {
+		if !v.HasLineage() {
+			// lineage of the view is unknown, so the whole view may be affected
+			dependents = append(dependents, w.view(v, d))
+			continue
+		}
+		columns := []*schema.Column{}
+		for _, vc := range v.Columns {
+			for _, l := range vc.Lineage {
+				if l.Table.Name == t.Name && l.Column.Name == c.Name {
+					columns = append(columns, vc)
+					break
+				}
+			}
+		}
+		if len(columns) == 0 {
+			continue
+		}
+		dep := &Dependent{
+			Table: v.Name,
+			Kind:  KindView,
+			Depth: d,
+		}
+		for _, vc := range columns {
+			dep.Columns = append(dep.Columns, vc.Name)
+			if w.visit(fmt.Sprintf("%s.%s", v.Name, vc.Name)) {
+				dep.Dependents = append(dep.Dependents, w.column(v, vc, d+1)...)
+			}
+		}
+		dependents = append(dependents, dep)
+	}
+	return dependents
+}
+
+func (w *walker) relation(r *schema.Relation, d int) *Dependent {
+	dep := &Dependent{
+		Table: r.Table.Name,
+		Kind:  KindForeignKey,
+		Def:   r.Def,
+		Depth: d,
+	}
+	if r.Virtual {
+		dep.Kind = KindVirtualRelation
+	}
+	for _, c := range r.Columns {
+		dep.Columns = append(dep.Columns, c.Name)
+		if w.visit(fmt.Sprintf("%s.%s", r.Table.Name, c.Name)) {
+			dep.Dependents = append(dep.Dependents, w.column(r.Table, c, d+1)...)
+		}
+	}
+	return dep
+}
+
+func (w *walker) view(v *schema.Table, d int) *Dependent {
+	dep := &Dependent{
+		Table: v.Name,
+		Kind:  KindView,
+		Depth: d,
+	}
+	if w.visit(v.Name) {
+		dep.Dependents = w.table(v, d+1)
+	}
+	return dep
+}
+
+// visit return false if key is already visited.
+func (w *walker) visit(key string) bool {
+	if _, ok := w.visited[key]; ok {
+		return false
+	}
+	w.visited[key] = struct{}{}
+	return true
+}
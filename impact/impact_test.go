@@ -0,0 +1,146 @@
+package impact
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tmdc-io/tbls/schema"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		target string
+		depth  int
+		want   *Impact
+	}{
+		{
+			"users",
+			0,
+			&Impact{
+				Table: "users",
+				Dependents: []*Dependent{
+					{Table: "posts", Columns: []string{"user_id"}, Kind: KindForeignKey, Def: "FOREIGN KEY (user_id) REFERENCES users(id)", Depth: 1},
+					{Table: "logs", Columns: []string{"user_id"}, Kind: KindVirtualRelation, Depth: 1},
+					{Table: "user_posts", Kind: KindView, Depth: 1, Dependents: []*Dependent{
+						{Table: "summary", Kind: KindView, Depth: 2},
+					}},
+				},
+			},
+		},
+		{
+			"users",
+			1,
+			&Impact{
+				Table: "users",
+				Dependents: []*Dependent{
+					{Table: "posts", Columns: []string{"user_id"}, Kind: KindForeignKey, Def: "FOREIGN KEY (user_id) REFERENCES users(id)", Depth: 1},
+					{Table: "logs", Columns: []string{"user_id"}, Kind: KindVirtualRelation, Depth: 1},
+					{Table: "user_posts", Kind: KindView, Depth: 1},
+				},
+			},
+		},
+		{
+			"posts.title",
+			0,
+			&Impact{
+				Table:  "posts",
+				Column: "title",
+				Dependents: []*Dependent{
+					{Table: "user_posts", Columns: []string{"title"}, Kind: KindView, Depth: 1, Dependents: []*Dependent{
+						{Table: "summary", Kind: KindView, Depth: 2},
+					}},
+				},
+			},
+		},
+		{
+			"posts.id",
+			0,
+			&Impact{
+				Table:      "posts",
+				Column:     "id",
+				Dependents: []*Dependent{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		s := newTestSchema()
+		ta, c, err := FindTarget(s, tt.target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := Analyze(s, ta, c, tt.depth)
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s: %s", tt.target, diff)
+		}
+	}
+}
+
+func TestFindTarget(t *testing.T) {
+	s := newTestSchema()
+	if _, _, err := FindTarget(s, "posts.unknown"); err == nil {
+		t.Error("want error")
+	}
+	if _, _, err := FindTarget(s, "unknown"); err == nil {
+		t.Error("want error")
+	}
+}
+
+func newTestSchema() *schema.Schema {
+	usersID := &schema.Column{Name: "id"}
+	users := &schema.Table{
+		Name:    "users",
+		Type:    "BASE TABLE",
+		Columns: []*schema.Column{usersID, &schema.Column{Name: "username"}},
+	}
+	postsUserID := &schema.Column{Name: "user_id"}
+	postsTitle := &schema.Column{Name: "title"}
+	posts := &schema.Table{
+		Name:    "posts",
+		Type:    "BASE TABLE",
+		Columns: []*schema.Column{&schema.Column{Name: "id"}, postsUserID, postsTitle},
+	}
+	logsUserID := &schema.Column{Name: "user_id"}
+	logs := &schema.Table{
+		Name:    "logs",
+		Type:    "BASE TABLE",
+		Columns: []*schema.Column{logsUserID},
+	}
+	userPosts := &schema.Table{
+		Name: "user_posts",
+		Type: "VIEW",
+		Columns: []*schema.Column{
+			&schema.Column{Name: "username", Lineage: []*schema.Lineage{&schema.Lineage{Table: users, Column: users.Columns[1]}}},
+			&schema.Column{Name: "title", Lineage: []*schema.Lineage{&schema.Lineage{Table: posts, Column: postsTitle}}},
+		},
+		ReferencedTables: []*schema.Table{users, posts},
+	}
+	summary := &schema.Table{
+		Name:             "summary",
+		Type:             "VIEW",
+		Columns:          []*schema.Column{&schema.Column{Name: "count"}},
+		ReferencedTables: []*schema.Table{userPosts},
+	}
+	r := &schema.Relation{
+		Table:         posts,
+		Columns:       []*schema.Column{postsUserID},
+		ParentTable:   users,
+		ParentColumns: []*schema.Column{usersID},
+		Def:           "FOREIGN KEY (user_id) REFERENCES users(id)",
+	}
+	vr := &schema.Relation{
+		Table:         logs,
+		Columns:       []*schema.Column{logsUserID},
+		ParentTable:   users,
+		ParentColumns: []*schema.Column{usersID},
+		Virtual:       true,
+	}
+	postsUserID.ParentRelations = []*schema.Relation{r}
+	logsUserID.ParentRelations = []*schema.Relation{vr}
+	usersID.ChildRelations = []*schema.Relation{r, vr}
+
+	return &schema.Schema{
+		Name:      "testschema",
+		Tables:    []*schema.Table{users, posts, logs, userPosts, summary},
+		Relations: []*schema.Relation{r, vr},
+	}
+}
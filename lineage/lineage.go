@@ -0,0 +1,100 @@
+// Package lineage resolves column-level lineage of views from their definitions.
+//
+// The parser is not a full SQL parser. It understands the subset of SELECT statements that view definitions of the supported databases are made of:
+//
+//   - `CREATE [OR REPLACE] [MATERIALIZED] VIEW name [(columns)] AS` ( or a bare query )
+//   - `WITH [RECURSIVE] name [(columns)] AS (query), ...` ( CTEs )
+//   - `SELECT [DISTINCT [ON (...)] | ALL] [TOP n [PERCENT]] items [INTO ...] FROM ...`
+//   - `UNION`, `INTERSECT`, `EXCEPT` and `MINUS` ( the sources of a column are merged by position )
+//   - FROM items: tables, CTEs, subqueries, `LATERAL` subqueries and comma, `[NATURAL] [INNER | LEFT | RIGHT | FULL] [OUTER] JOIN` and `CROSS JOIN` with `ON` or `USING`
+//   - array columns of a preceding FROM item ( `FROM t, t.items AS item` of BigQuery )
+//   - identifiers quoted with `"`, backquotes or `[]`, and casts ( `CAST(x AS t)`, `x::t`, `CONVERT(t, x)` )
+//
+// The sources of an expression are all columns referenced in it ( including CASE conditions and window clauses ).
+// Clauses after FROM ( WHERE, GROUP BY, ORDER BY, ... ) do not affect lineage and are skipped.
+// An unqualified column of `USING` resolves to the left table. Columns of `VALUES` lists have no sources.
+// A definition that is not a query, or that has unbalanced parentheses, unterminated quotes or an empty select list, is an error.
+package lineage
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tmdc-io/tbls/schema"
+)
+
+// Analyze resolves which source columns feed each column of views and stores it as schema.Column.Lineage.
+// Views whose definition cannot be parsed are skipped ( logged at debug level ).
+func Analyze(s *schema.Schema) {
+	catalog := func(name string) ([]string, bool) {
+		t := findTable(s, name)
+		if t == nil {
+			return nil, false
+		}
+		columns := []string{}
+		for _, c := range t.Columns {
+			columns = append(columns, c.Name)
+		}
+		return columns, true
+	}
+	for _, t := range s.Tables {
+		if !strings.Contains(strings.ToUpper(t.Type), "VIEW") || t.Def == "" {
+			continue
+		}
+		columns, err := Parse(t.Def, catalog)
+		if err != nil {
+			log.Debugf("failed to parse definition of '%s' for lineage: %s", t.Name, err)
+			continue
+		}
+		for i, c := range t.Columns {
+			var lc *Column
+			for j := range columns {
+				if strings.EqualFold(columns[j].Name, c.Name) {
+					lc = &columns[j]
+					break
+				}
+			}
+			// unnamed expressions are matched by position
+			if lc == nil && len(columns) == len(t.Columns) {
+				lc = &columns[i]
+			}
+			if lc == nil {
+				continue
+			}
+			c.Lineage = nil
+			for _, src := range lc.Sources {
+				c.Lineage = append(c.Lineage, newLineage(s, src))
+			}
+		}
+	}
+}
+
+func newLineage(s *schema.Schema, src Source) *schema.Lineage {
+	t := findTable(s, src.Table)
+	if t == nil {
+		return &schema.Lineage{
+			Table:  &schema.Table{Name: src.Table, External: true},
+			Column: &schema.Column{Name: src.Column},
+		}
+	}
+	for _, c := range t.Columns {
+		if strings.EqualFold(c.Name, src.Column) {
+			return &schema.Lineage{Table: t, Column: c}
+		}
+	}
+	return &schema.Lineage{Table: t, Column: &schema.Column{Name: src.Column}}
+}
+
+// findTable find table by a name written in a view definition ( e.g. `testdb.posts` for `posts` ).
+func findTable(s *schema.Schema, name string) *schema.Table {
+	candidates := []string{name, strings.TrimPrefix(name, fmt.Sprintf("%s.", s.Name))}
+	splitted := strings.Split(name, ".")
+	candidates = append(candidates, splitted[len(splitted)-1])
+	for _, n := range candidates {
+		if t, err := s.FindTableByName(n); err == nil {
+			return t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package lineage
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var testCatalog = map[string][]string{
+	"posts":                  {"id", "user_id", "title", "body", "created"},
+	"comments":               {"id", "post_id", "user_id", "comment", "created"},
+	"users":                  {"id", "username", "email"},
+	"testdb.posts":           {"id", "user_id", "title", "body", "created"},
+	"testdb.users":           {"id", "username", "email"},
+	"project.dataset.orders": {"id", "items", "items.name"},
+}
+
+func catalog(table string) ([]string, bool) {
+	c, ok := testCatalog[table]
+	return c, ok
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []Column
+	}{
+		{
+			`SELECT id, title AS subject FROM posts`,
+			[]Column{
+				{Name: "id", Sources: []Source{{"posts", "id"}}},
+				{Name: "subject", Sources: []Source{{"posts", "title"}}},
+			},
+		},
+		{
+			`CREATE VIEW post_comments AS (
+ SELECT c.id,
+    p.title,
+    u.username AS post_user,
+    c.comment,
+    u2.username AS comment_user,
+    c.created
+   FROM (((posts p
+     LEFT JOIN comments c ON ((p.id = c.post_id)))
+     LEFT JOIN users u ON ((u.id = p.user_id)))
+     LEFT JOIN users u2 ON ((u2.id = c.user_id)))
+)`,
+			[]Column{
+				{Name: "id", Sources: []Source{{"comments", "id"}}},
+				{Name: "title", Sources: []Source{{"posts", "title"}}},
+				{Name: "post_user", Sources: []Source{{"users", "username"}}},
+				{Name: "comment", Sources: []Source{{"comments", "comment"}}},
+				{Name: "comment_user", Sources: []Source{{"users", "username"}}},
+				{Name: "created", Sources: []Source{{"comments", "created"}}},
+			},
+		},
+		{
+			"CREATE VIEW post_users AS select `p`.`id` AS `id`,concat(`u`.`username`, ': ', `p`.`title`) AS `label` from (`testdb`.`posts` `p` left join `testdb`.`users` `u` on((`u`.`id` = `p`.`user_id`)))",
+			[]Column{
+				{Name: "id", Sources: []Source{{"testdb.posts", "id"}}},
+				{Name: "label", Sources: []Source{{"testdb.users", "username"}, {"testdb.posts", "title"}}},
+			},
+		},
+		{
+			`CREATE VIEW recent AS
+WITH recent_posts AS (SELECT id AS post_id, title FROM posts WHERE created > CURRENT_DATE - INTERVAL '7 days')
+SELECT r.title, count(*) AS comment_count, max(c.created) last_commented
+FROM recent_posts r JOIN comments c USING (post_id)
+GROUP BY r.title`,
+			[]Column{
+				{Name: "title", Sources: []Source{{"posts", "title"}}},
+				{Name: "comment_count", Sources: []Source{}},
+				{Name: "last_commented", Sources: []Source{{"comments", "created"}}},
+			},
+		},
+		{
+			`SELECT s.uid, s.n FROM (SELECT user_id AS uid, count(id) n FROM posts GROUP BY user_id) s`,
+			[]Column{
+				{Name: "uid", Sources: []Source{{"posts", "user_id"}}},
+				{Name: "n", Sources: []Source{{"posts", "id"}}},
+			},
+		},
+		{
+			`SELECT u.*, (SELECT count(*) FROM posts p WHERE p.user_id = u.id) AS posts FROM users u`,
+			[]Column{
+				{Name: "id", Sources: []Source{{"users", "id"}}},
+				{Name: "username", Sources: []Source{{"users", "username"}}},
+				{Name: "email", Sources: []Source{{"users", "email"}}},
+				{Name: "posts", Sources: []Source{}},
+			},
+		},
+		{
+			`SELECT id, username AS name FROM users UNION ALL SELECT id, title FROM posts ORDER BY 1`,
+			[]Column{
+				{Name: "id", Sources: []Source{{"users", "id"}, {"posts", "id"}}},
+				{Name: "name", Sources: []Source{{"users", "username"}, {"posts", "title"}}},
+			},
+		},
+		{
+			`CREATE VIEW v (a, b) AS SELECT CAST(p.id AS varchar(10)), p.title::text || ' by ' || u.username FROM posts p, users u WHERE p.user_id = u.id`,
+			[]Column{
+				{Name: "a", Sources: []Source{{"posts", "id"}}},
+				{Name: "b", Sources: []Source{{"posts", "title"}, {"users", "username"}}},
+			},
+		},
+		{
+			`SELECT CASE WHEN EXTRACT(YEAR FROM created) > 2020 THEN title ELSE 'old' END AS label FROM posts`,
+			[]Column{
+				{Name: "label", Sources: []Source{{"posts", "created"}, {"posts", "title"}}},
+			},
+		},
+		{
+			`WITH RECURSIVE r(id) AS (SELECT id FROM posts UNION ALL SELECT p.id FROM posts p JOIN r ON p.user_id = r.id), t AS (SELECT r.id FROM r) SELECT t.id FROM t`,
+			[]Column{
+				{Name: "id", Sources: []Source{{"posts", "id"}}},
+			},
+		},
+		{
+			`SELECT id, comment FROM posts JOIN comments USING (id)`,
+			[]Column{
+				{Name: "id", Sources: []Source{{"posts", "id"}}},
+				{Name: "comment", Sources: []Source{{"comments", "comment"}}},
+			},
+		},
+		{
+			`SELECT p.id::varchar(10) AS pid, CONVERT(varchar, p.created, 120) AS c, TRY_CAST(p.user_id AS int) AS u FROM posts p`,
+			[]Column{
+				{Name: "pid", Sources: []Source{{"posts", "id"}}},
+				{Name: "c", Sources: []Source{{"posts", "created"}}},
+				{Name: "u", Sources: []Source{{"posts", "user_id"}}},
+			},
+		},
+		{
+			`SELECT "p"."id", "Title" FROM "posts" AS "p"`,
+			[]Column{
+				{Name: "id", Sources: []Source{{"posts", "id"}}},
+				{Name: "Title", Sources: []Source{{"posts", "title"}}},
+			},
+		},
+		{
+			`SELECT TOP 10 [p].[id], [p].[title] AS [the title] FROM [posts] [p]`,
+			[]Column{
+				{Name: "id", Sources: []Source{{"posts", "id"}}},
+				{Name: "the title", Sources: []Source{{"posts", "title"}}},
+			},
+		},
+		{
+			`SELECT * FROM (VALUES (1, 'a')) AS v(id, name)`,
+			[]Column{
+				{Name: "id", Sources: []Source{}},
+				{Name: "name", Sources: []Source{}},
+			},
+		},
+		{
+			"SELECT t.id, item.name, item.qty FROM `project.dataset.orders` AS t, t.items AS item",
+			[]Column{
+				{Name: "id", Sources: []Source{{"project.dataset.orders", "id"}}},
+				{Name: "name", Sources: []Source{{"project.dataset.orders", "items.name"}}},
+				{Name: "qty", Sources: []Source{{"project.dataset.orders", "items"}}},
+			},
+		},
+		{
+			`SELECT a.x, y FROM external_table a`,
+			[]Column{
+				{Name: "x", Sources: []Source{{"external_table", "x"}}},
+				{Name: "y", Sources: []Source{{"external_table", "y"}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in, catalog)
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+			continue
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s\n%s", tt.in, diff)
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []string{
+		`CREATE TABLE posts (id int)`,
+		`SELECT 'unterminated FROM posts`,
+		`SELECT "unterminated FROM posts`,
+		`SELECT [unterminated FROM posts`,
+		`SELECT id /* unterminated FROM posts`,
+		`SELECT (id FROM posts`,
+		`SELECT id) FROM posts`,
+		`SELECT FROM posts`,
+		`SELECT id FROM`,
+		`UPDATE posts SET id = 1`,
+		`CREATE VIEW v AS`,
+	}
+	for _, tt := range tests {
+		if _, err := Parse(tt, catalog); err == nil {
+			t.Errorf("%s: want error", tt)
+		}
+	}
+}
@@ -0,0 +1,839 @@
+package lineage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Source is a column of a table (or view) that feeds an output column
+type Source struct {
+	Table  string
+	Column string
+}
+
+// Column is an output column of a query and the source columns it is derived from
+type Column struct {
+	Name    string
+	Sources []Source
+}
+
+// Catalog return column names of the table, and false if the table is unknown
+type Catalog func(table string) ([]string, bool)
+
+// words that are keywords in expressions but may still be used as column names
+var softKeywords = map[string]struct{}{}
+
+func init() {
+	for _, k := range []string{
+		"AT", "BOTH", "COLLATE", "CURRENT", "DATE", "DAY", "EPOCH", "ESCAPE", "FILTER", "FIRST", "FOLLOWING",
+		"HOUR", "IGNORE", "LAST", "LEADING", "MINUTE", "MONTH", "NULLS", "PARTITION", "PRECEDING", "RANGE",
+		"RESPECT", "ROW", "ROWS", "SECOND", "SEPARATOR", "TIME", "TIMESTAMP", "TRAILING", "UNBOUNDED",
+		"WITHIN", "YEAR", "ZONE",
+	} {
+		softKeywords[k] = struct{}{}
+	}
+}
+
+// relation is a table, view, CTE or subquery visible in a FROM clause
+type relation struct {
+	alias   string
+	name    string   // table name of a base table
+	columns []Column // output columns of a derived table or known columns of a base table
+	base    bool
+	known   bool // columns are known
+	// array column of a preceding FROM item that the relation unnests ( e.g. `FROM t, t.items AS item` of BigQuery )
+	parent *relation
+	path   string
+}
+
+type scope struct {
+	parent    *scope
+	relations []*relation
+	ctes      map[string]*relation
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{
+		parent: parent,
+		ctes:   map[string]*relation{},
+	}
+}
+
+func (sc *scope) lookupCTE(name string) *relation {
+	for s := sc; s != nil; s = s.parent {
+		if r, ok := s.ctes[strings.ToLower(name)]; ok {
+			return r
+		}
+	}
+	return nil
+}
+
+// lookupAlias find a preceding FROM item of the scope by alias
+func (sc *scope) lookupAlias(alias string) *relation {
+	for _, r := range sc.relations {
+		if strings.EqualFold(r.alias, alias) {
+			return r
+		}
+	}
+	return nil
+}
+
+type parser struct {
+	tokens  []token
+	pos     int
+	catalog Catalog
+}
+
+// Parse parses a view definition ( `CREATE VIEW ... AS SELECT ...` or a bare query ) and resolves the source columns of each output column.
+func Parse(def string, catalog Catalog) ([]Column, error) {
+	tokens, err := tokenize(def)
+	if err != nil {
+		return nil, err
+	}
+	if catalog == nil {
+		catalog = func(string) ([]string, bool) { return nil, false }
+	}
+	p := &parser{tokens: tokens, catalog: catalog}
+	names, err := p.skipCreate()
+	if err != nil {
+		return nil, err
+	}
+	columns, err := p.parseQuery(newScope(nil))
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().is(";") {
+		p.next()
+	}
+	for i, n := range names {
+		if i < len(columns) {
+			columns[i].Name = n
+		}
+	}
+	for i := range columns {
+		columns[i].Sources = uniqueSources(columns[i].Sources)
+	}
+	return columns, nil
+}
+
+func (p *parser) peek() token {
+	return p.peekAt(0)
+}
+
+func (p *parser) peekAt(n int) token {
+	if p.pos+n >= len(p.tokens) {
+		return token{kind: tkSymbol, val: ""}
+	}
+	return p.tokens[p.pos+n]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) expect(v string) error {
+	t := p.next()
+	if !t.is(v) {
+		return errors.Errorf("expected '%s' but got '%s'", v, t.val)
+	}
+	return nil
+}
+
+// skipCreate skip `CREATE [OR REPLACE] [MATERIALIZED] VIEW name [(columns)] [WITH ...] AS` and return the view column list.
+func (p *parser) skipCreate() ([]string, error) {
+	if !p.peek().is("CREATE") {
+		return nil, nil
+	}
+	for !p.eof() && !p.peek().is("VIEW") {
+		p.next()
+	}
+	if p.eof() {
+		return nil, errors.New("not a view definition")
+	}
+	p.next()
+	p.parseName()
+	names := []string{}
+	if p.peek().is("(") {
+		p.next()
+		for !p.eof() && !p.peek().is(")") {
+			t := p.next()
+			if t.is(",") {
+				continue
+			}
+			names = append(names, t.val)
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+	depth := 0
+	for !p.eof() {
+		t := p.next()
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			depth--
+		case t.is("AS") && depth == 0:
+			return names, nil
+		}
+	}
+	return nil, errors.New("not a view definition")
+}
+
+// parseName parse a dotted name and return its parts.
+func (p *parser) parseName() []string {
+	parts := []string{p.next().val}
+	for p.peek().is(".") && (p.peekAt(1).kind == tkIdent || p.peekAt(1).kind == tkQuotedIdent) {
+		p.next()
+		parts = append(parts, p.next().val)
+	}
+	return parts
+}
+
+// parseQuery parse `[WITH ...] select [UNION select ...] [ORDER BY ...] [LIMIT ...]`.
+func (p *parser) parseQuery(sc *scope) ([]Column, error) {
+	if p.peek().is("WITH") {
+		p.next()
+		if p.peek().is("RECURSIVE") {
+			p.next()
+		}
+		sc = newScope(sc)
+		for {
+			name := p.next()
+			if !name.isIdent() {
+				return nil, errors.Errorf("invalid CTE name '%s'", name.val)
+			}
+			names := []string{}
+			if p.peek().is("(") {
+				p.next()
+				for !p.eof() && !p.peek().is(")") {
+					t := p.next()
+					if !t.is(",") {
+						names = append(names, t.val)
+					}
+				}
+				if err := p.expect(")"); err != nil {
+					return nil, err
+				}
+			}
+			if err := p.expect("AS"); err != nil {
+				return nil, err
+			}
+			if p.peek().is("NOT") {
+				p.next()
+			}
+			if p.peek().is("MATERIALIZED") {
+				p.next()
+			}
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			// a recursive reference to the CTE itself yields no sources
+			cte := &relation{alias: name.val, known: true}
+			sc.ctes[strings.ToLower(name.val)] = cte
+			columns, err := p.parseQuery(sc)
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			for i, n := range names {
+				if i < len(columns) {
+					columns[i].Name = n
+				}
+			}
+			cte.columns = columns
+			if !p.peek().is(",") {
+				break
+			}
+			p.next()
+		}
+	}
+
+	columns, err := p.parsePrimary(sc)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if !t.is("UNION") && !t.is("INTERSECT") && !t.is("EXCEPT") && !t.is("MINUS") {
+			break
+		}
+		p.next()
+		if p.peek().is("ALL") || p.peek().is("DISTINCT") {
+			p.next()
+		}
+		other, err := p.parsePrimary(sc)
+		if err != nil {
+			return nil, err
+		}
+		for i := range columns {
+			if i < len(other) {
+				columns[i].Sources = append(columns[i].Sources, other[i].Sources...)
+			}
+		}
+	}
+	// ORDER BY, LIMIT, ...
+	p.skipUntil(func(t token) bool { return false })
+	return columns, nil
+}
+
+func (p *parser) parsePrimary(sc *scope) ([]Column, error) {
+	t := p.peek()
+	switch {
+	case t.is("("):
+		p.next()
+		columns, err := p.parseQuery(sc)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return columns, nil
+	case t.is("SELECT"):
+		return p.parseSelect(sc)
+	case t.is("VALUES"):
+		p.next()
+		n := 0
+		if p.peek().is("(") {
+			start := p.pos
+			p.skipParens()
+			n = len(splitTopLevel(p.tokens[start+1 : p.pos-1]))
+		}
+		p.skipUntil(func(t token) bool { return isSetOperator(t) })
+		return make([]Column, n), nil
+	}
+	return nil, errors.Errorf("expected SELECT but got '%s'", t.val)
+}
+
+func (p *parser) parseSelect(sc *scope) ([]Column, error) {
+	p.next()
+	switch {
+	case p.peek().is("DISTINCT"):
+		p.next()
+		if p.peek().is("ON") {
+			p.next()
+			p.skipParens()
+		}
+	case p.peek().is("ALL"):
+		p.next()
+	}
+	if p.peek().is("TOP") {
+		p.next()
+		if p.peek().is("(") {
+			p.skipParens()
+		} else {
+			p.next()
+		}
+		if p.peek().is("PERCENT") {
+			p.next()
+		}
+	}
+
+	start := p.pos
+	p.skipUntil(func(t token) bool {
+		return t.is("FROM") || t.is("INTO") || isClauseKeyword(t) || isSetOperator(t)
+	})
+	if p.pos == start {
+		return nil, errors.New("empty select list")
+	}
+	items := splitTopLevel(p.tokens[start:p.pos])
+
+	local := newScope(sc)
+	if p.peek().is("INTO") {
+		p.skipUntil(func(t token) bool { return t.is("FROM") || isClauseKeyword(t) || isSetOperator(t) })
+	}
+	if p.peek().is("FROM") {
+		p.next()
+		if err := p.parseFromList(local); err != nil {
+			return nil, err
+		}
+	}
+	// WHERE, GROUP BY, HAVING, ...
+	p.skipUntil(isSetOperator)
+
+	columns := []Column{}
+	for _, item := range items {
+		cs, err := p.resolveItem(item, local)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, cs...)
+	}
+	return columns, nil
+}
+
+func (p *parser) parseFromList(sc *scope) error {
+	for {
+		if err := p.parseFromItem(sc); err != nil {
+			return err
+		}
+		for {
+			t := p.peek()
+			if t.is(",") {
+				p.next()
+				break
+			}
+			if !isJoinKeyword(t) {
+				return nil
+			}
+			for isJoinKeyword(p.peek()) && !p.peek().is("JOIN") && !p.peek().is("APPLY") && !p.peek().is("STRAIGHT_JOIN") {
+				p.next()
+			}
+			p.next()
+			if err := p.parseFromItem(sc); err != nil {
+				return err
+			}
+			switch {
+			case p.peek().is("ON"):
+				p.next()
+				p.skipUntil(func(t token) bool {
+					return t.is(",") || (isJoinKeyword(t) && !p.peekAt(1).is("(")) || isClauseKeyword(t) || isSetOperator(t)
+				})
+			case p.peek().is("USING"):
+				p.next()
+				p.skipParens()
+			}
+		}
+	}
+}
+
+func (p *parser) parseFromItem(sc *scope) error {
+	if p.peek().is("LATERAL") || p.peek().is("ONLY") {
+		p.next()
+	}
+	var rel *relation
+	switch {
+	case p.peek().is("("):
+		i := 1
+		for p.peekAt(i).is("(") {
+			i++
+		}
+		t := p.peekAt(i)
+		if t.is("SELECT") || t.is("WITH") || t.is("VALUES") {
+			p.next()
+			columns, err := p.parseQuery(sc)
+			if err != nil {
+				return err
+			}
+			if err := p.expect(")"); err != nil {
+				return err
+			}
+			rel = &relation{columns: columns, known: true}
+		} else {
+			// parenthesized joins
+			p.next()
+			if err := p.parseFromList(sc); err != nil {
+				return err
+			}
+			if err := p.expect(")"); err != nil {
+				return err
+			}
+			p.parseAlias()
+			return nil
+		}
+	case p.peek().kind == tkIdent || p.peek().kind == tkQuotedIdent:
+		parts := p.parseName()
+		name := strings.Join(parts, ".")
+		if p.peek().is("(") {
+			// table function
+			p.skipParens()
+			rel = &relation{alias: parts[len(parts)-1], known: true}
+			break
+		}
+		splitted := strings.Split(name, ".")
+		alias := splitted[len(splitted)-1]
+		if len(parts) > 1 {
+			if parent := sc.lookupAlias(strings.Join(parts[:len(parts)-1], ".")); parent != nil {
+				rel = &relation{alias: alias, parent: parent, path: parts[len(parts)-1]}
+				break
+			}
+		}
+		if cte := sc.lookupCTE(name); cte != nil && len(parts) == 1 {
+			rel = &relation{alias: alias, columns: cte.columns, known: true}
+			break
+		}
+		rel = &relation{alias: alias, name: name, base: true}
+		if columns, ok := p.catalog(name); ok {
+			rel.known = true
+			for _, c := range columns {
+				rel.columns = append(rel.columns, Column{Name: c, Sources: []Source{{Table: name, Column: c}}})
+			}
+		}
+	default:
+		return errors.Errorf("unexpected token '%s' in FROM clause", p.peek().val)
+	}
+
+	// table hints ( e.g. `WITH (NOLOCK)` )
+	if p.peek().is("WITH") && p.peekAt(1).is("(") {
+		p.next()
+		p.skipParens()
+	}
+	alias, names := p.parseAlias()
+	if alias != "" {
+		rel.alias = alias
+	}
+	if len(names) > 0 {
+		columns := make([]Column, len(rel.columns))
+		copy(columns, rel.columns)
+		for i, n := range names {
+			if i < len(columns) {
+				columns[i].Name = n
+			}
+		}
+		rel.columns = columns
+	}
+	sc.relations = append(sc.relations, rel)
+	return nil
+}
+
+// parseAlias parse `[AS] alias [(column, ...)]`.
+func (p *parser) parseAlias() (string, []string) {
+	if p.peek().is("AS") {
+		p.next()
+	} else if !p.peek().isIdent() || isJoinKeyword(p.peek()) || isSoftClause(p.peek()) {
+		return "", nil
+	}
+	alias := p.next().val
+	names := []string{}
+	if p.peek().is("(") {
+		start := p.pos
+		p.skipParens()
+		for _, t := range p.tokens[start+1 : p.pos-1] {
+			if !t.is(",") {
+				names = append(names, t.val)
+			}
+		}
+	}
+	return alias, names
+}
+
+// skipParens skip a balanced parenthesized token group starting at the current token.
+func (p *parser) skipParens() {
+	if !p.peek().is("(") {
+		return
+	}
+	depth := 0
+	for !p.eof() {
+		t := p.next()
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			depth--
+			if depth == 0 {
+				return
+			}
+		}
+	}
+}
+
+// skipUntil skip tokens until stop returns true at the top level, a closing parenthesis or the end of statement.
+func (p *parser) skipUntil(stop func(t token) bool) {
+	depth := 0
+	for !p.eof() {
+		t := p.peek()
+		if depth == 0 && (stop(t) || t.is(")") || t.is(";")) {
+			return
+		}
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			depth--
+		}
+		p.next()
+	}
+}
+
+// resolveItem resolve a select list item into output columns.
+func (p *parser) resolveItem(item []token, sc *scope) ([]Column, error) {
+	n := len(item)
+	if n == 0 {
+		return nil, errors.New("empty select item")
+	}
+	// * and t.*
+	if item[n-1].is("*") && (n == 1 || item[n-2].is(".")) {
+		qualifier := []string{}
+		for _, t := range item[:n-1] {
+			if !t.is(".") {
+				qualifier = append(qualifier, t.val)
+			}
+		}
+		columns := []Column{}
+		for _, r := range sc.relations {
+			if len(qualifier) > 0 && !r.matches(qualifier) {
+				continue
+			}
+			for _, c := range r.columns {
+				columns = append(columns, Column{Name: c.Name, Sources: append([]Source{}, c.Sources...)})
+			}
+		}
+		return columns, nil
+	}
+
+	expr := item
+	name := ""
+	switch {
+	case n >= 2 && item[n-2].is("AS"):
+		name = item[n-1].val
+		expr = item[:n-2]
+	case n >= 2 && item[n-1].isIdent() && endsExpr(item[n-2]):
+		name = item[n-1].val
+		expr = item[:n-1]
+	}
+	if name == "" && (expr[len(expr)-1].kind == tkIdent || expr[len(expr)-1].kind == tkQuotedIdent) {
+		ref := true
+		for i, t := range expr {
+			if (i%2 == 0 && !t.isIdent()) || (i%2 == 1 && !t.is(".")) {
+				ref = false
+				break
+			}
+		}
+		if ref {
+			name = expr[len(expr)-1].val
+		}
+	}
+	sources, err := p.exprSources(expr, sc)
+	if err != nil {
+		return nil, err
+	}
+	return []Column{{Name: name, Sources: sources}}, nil
+}
+
+// exprSources list the source columns referenced by an expression.
+func (p *parser) exprSources(expr []token, sc *scope) ([]Source, error) {
+	sources := []Source{}
+	for i := 0; i < len(expr); i++ {
+		t := expr[i]
+		switch {
+		case t.is("("):
+			j := i + 1
+			for j < len(expr) && expr[j].is("(") {
+				j++
+			}
+			if j < len(expr) && (expr[j].is("SELECT") || expr[j].is("WITH")) {
+				end := matchParen(expr, i)
+				sub := &parser{tokens: expr[i+1 : end], catalog: p.catalog}
+				columns, err := sub.parseQuery(sc)
+				if err != nil {
+					return nil, err
+				}
+				for _, c := range columns {
+					sources = append(sources, c.Sources...)
+				}
+				i = end
+			}
+		case t.is("::"), t.is("AS"):
+			// type cast
+			for i+1 < len(expr) && expr[i+1].isIdent() {
+				i++
+			}
+			if i+1 < len(expr) && expr[i+1].is("(") {
+				i = matchParen(expr, i+1)
+			}
+			for i+2 < len(expr) && expr[i+1].is("[") && expr[i+2].is("]") {
+				i += 2
+			}
+		case t.kind == tkIdent && isReserved(t.val):
+		case t.kind == tkIdent || t.kind == tkQuotedIdent:
+			parts := []string{t.val}
+			for i+2 < len(expr) && expr[i+1].is(".") && (expr[i+2].kind == tkIdent || expr[i+2].kind == tkQuotedIdent) {
+				parts = append(parts, expr[i+2].val)
+				i += 2
+			}
+			if i+1 < len(expr) {
+				nt := expr[i+1]
+				// function call, typed literal ( e.g. DATE '2020-01-01' ) or field ( e.g. EXTRACT(YEAR FROM ...) )
+				if nt.is("(") || nt.kind == tkString || (t.kind == tkIdent && nt.is("FROM")) {
+					continue
+				}
+			}
+			_, soft := softKeywords[strings.ToUpper(t.val)]
+			soft = soft && t.kind == tkIdent && len(parts) == 1
+			sources = append(sources, sc.resolve(parts[:len(parts)-1], parts[len(parts)-1], soft)...)
+		}
+	}
+	return sources, nil
+}
+
+// resolve resolve a column reference to its source columns.
+func (sc *scope) resolve(qualifier []string, column string, soft bool) []Source {
+	for s := sc; s != nil; s = s.parent {
+		if len(qualifier) > 0 {
+			for _, r := range s.relations {
+				if r.matches(qualifier) {
+					return r.sourcesOf(column, true)
+				}
+			}
+			continue
+		}
+		for _, r := range s.relations {
+			if !r.known {
+				continue
+			}
+			if sources := r.sourcesOf(column, false); sources != nil {
+				return sources
+			}
+		}
+		if soft {
+			continue
+		}
+		unknown := []*relation{}
+		for _, r := range s.relations {
+			if !r.known {
+				unknown = append(unknown, r)
+			}
+		}
+		if len(unknown) == 1 {
+			return unknown[0].sourcesOf(column, true)
+		}
+		if len(unknown) > 1 {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *relation) matches(qualifier []string) bool {
+	q := strings.Join(qualifier, ".")
+	if strings.EqualFold(r.alias, q) {
+		return true
+	}
+	if !r.base {
+		return false
+	}
+	return strings.EqualFold(r.name, q) || strings.HasSuffix(strings.ToLower(r.name), "."+strings.ToLower(q))
+}
+
+// sourcesOf return source columns of a column of the relation. when force is true, an unknown column of base table is also treated as source.
+func (r *relation) sourcesOf(column string, force bool) []Source {
+	if r.parent != nil {
+		if sources := r.parent.sourcesOf(fmt.Sprintf("%s.%s", r.path, column), false); len(sources) > 0 {
+			return sources
+		}
+		return r.parent.sourcesOf(r.path, true)
+	}
+	for _, c := range r.columns {
+		if strings.EqualFold(c.Name, column) {
+			if c.Sources == nil {
+				return []Source{}
+			}
+			return c.Sources
+		}
+	}
+	if r.base && (!r.known || force) {
+		return []Source{{Table: r.name, Column: column}}
+	}
+	if force {
+		return []Source{}
+	}
+	return nil
+}
+
+// splitTopLevel split tokens by commas outside of parentheses.
+func splitTopLevel(tokens []token) [][]token {
+	items := [][]token{}
+	depth := 0
+	start := 0
+	for i, t := range tokens {
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			depth--
+		case t.is(",") && depth == 0:
+			items = append(items, tokens[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		items = append(items, tokens[start:])
+	}
+	return items
+}
+
+func matchParen(tokens []token, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		switch {
+		case tokens[i].is("("):
+			depth++
+		case tokens[i].is(")"):
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+// endsExpr reports whether the token can be the last token of an expression, so that an identifier following it is an alias.
+func endsExpr(t token) bool {
+	switch t.kind {
+	case tkQuotedIdent, tkString, tkNumber:
+		return true
+	case tkSymbol:
+		return t.val == ")"
+	}
+	for _, k := range []string{"END", "NULL", "TRUE", "FALSE", "CURRENT_DATE", "CURRENT_TIME", "CURRENT_TIMESTAMP", "LOCALTIME", "LOCALTIMESTAMP", "CURRENT_USER", "SESSION_USER"} {
+		if t.is(k) {
+			return true
+		}
+	}
+	return !isReserved(t.val)
+}
+
+func isSetOperator(t token) bool {
+	return t.is("UNION") || t.is("INTERSECT") || t.is("EXCEPT") || t.is("MINUS")
+}
+
+func isClauseKeyword(t token) bool {
+	for _, k := range []string{"WHERE", "GROUP", "HAVING", "WINDOW", "QUALIFY", "ORDER", "LIMIT", "OFFSET", "FETCH", "FOR"} {
+		if t.is(k) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSoftClause reports whether a non-reserved word starts a clause after a FROM item.
+func isSoftClause(t token) bool {
+	return t.kind == tkIdent && (t.is("TABLESAMPLE") || t.is("START") || t.is("CONNECT") || t.is("PIVOT") || t.is("UNPIVOT"))
+}
+
+func isJoinKeyword(t token) bool {
+	for _, k := range []string{"JOIN", "INNER", "LEFT", "RIGHT", "FULL", "CROSS", "NATURAL", "OUTER", "APPLY", "STRAIGHT_JOIN"} {
+		if t.is(k) {
+			return true
+		}
+	}
+	return false
+}
+
+func uniqueSources(in []Source) []Source {
+	u := []Source{}
+	m := map[Source]struct{}{}
+	for _, s := range in {
+		if _, ok := m[s]; ok {
+			continue
+		}
+		u = append(u, s)
+		m[s] = struct{}{}
+	}
+	return u
+}
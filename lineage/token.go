@@ -0,0 +1,182 @@
+package lineage
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+type tokenKind int
+
+const (
+	tkIdent tokenKind = iota
+	tkQuotedIdent
+	tkString
+	tkNumber
+	tkSymbol
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+}
+
+// is reports whether the token is the unquoted keyword or the symbol v.
+func (t token) is(v string) bool {
+	switch t.kind {
+	case tkIdent:
+		return strings.EqualFold(t.val, v)
+	case tkSymbol:
+		return t.val == v
+	}
+	return false
+}
+
+// isIdent reports whether the token can be used as a name.
+func (t token) isIdent() bool {
+	if t.kind == tkQuotedIdent {
+		return true
+	}
+	return t.kind == tkIdent && !isReserved(t.val)
+}
+
+// tokenize split SQL into tokens, dropping whitespace and comments.
+func tokenize(src string) ([]token, error) {
+	tokens := []token{}
+	i := 0
+	for i < len(src) {
+		r, w := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += w
+		case strings.HasPrefix(src[i:], "--"):
+			end := strings.Index(src[i:], "\n")
+			if end < 0 {
+				return tokens, nil
+			}
+			i += end + 1
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, errors.New("unterminated comment")
+			}
+			i += end + 4
+		case r == '\'':
+			end, err := closing(src, i, '\'')
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tkString, val: src[i+1 : end]})
+			i = end + 1
+		case r == '"' || r == '`':
+			end, err := closing(src, i, byte(r))
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tkQuotedIdent, val: strings.ReplaceAll(src[i+1:end], string(r)+string(r), string(r))})
+			i = end + 1
+		case r == '[':
+			end := strings.Index(src[i:], "]")
+			if end < 0 {
+				return nil, errors.New("unterminated bracket identifier")
+			}
+			tokens = append(tokens, token{kind: tkQuotedIdent, val: src[i+1 : i+end]})
+			i += end + 1
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(src) && (isDigit(src[j]) || src[j] == '.' || src[j] == 'e' || src[j] == 'E') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tkNumber, val: src[i:j]})
+			i = j
+		case isIdentStart(r):
+			j := i + w
+			for j < len(src) {
+				r2, w2 := utf8.DecodeRuneInString(src[j:])
+				if !isIdentPart(r2) {
+					break
+				}
+				j += w2
+			}
+			tokens = append(tokens, token{kind: tkIdent, val: src[i:j]})
+			i = j
+		default:
+			sym := src[i : i+w]
+			for _, op := range []string{"::", "||", "<=", ">=", "<>", "!=", "->>", "->", "=>"} {
+				if strings.HasPrefix(src[i:], op) {
+					sym = op
+					break
+				}
+			}
+			tokens = append(tokens, token{kind: tkSymbol, val: sym})
+			i += len(sym)
+		}
+	}
+	depth := 0
+	for _, t := range tokens {
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			depth--
+		}
+		if depth < 0 {
+			return nil, errors.New("unbalanced parentheses")
+		}
+	}
+	if depth != 0 {
+		return nil, errors.New("unbalanced parentheses")
+	}
+	return tokens, nil
+}
+
+// closing return the index of the quote closing the one at src[start], honoring doubled quotes.
+func closing(src string, start int, q byte) (int, error) {
+	for i := start + 1; i < len(src); i++ {
+		if src[i] != q {
+			continue
+		}
+		if i+1 < len(src) && src[i+1] == q {
+			i++
+			continue
+		}
+		return i, nil
+	}
+	return 0, errors.Errorf("unterminated quote %q", q)
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return r == '_' || r == '$' || r == '#' || r == '@' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// reserved keywords never used as column names or aliases without quoting
+var reserved = map[string]struct{}{}
+
+func init() {
+	for _, k := range []string{
+		"ALL", "AND", "ANY", "ARRAY", "AS", "ASC", "BETWEEN", "BY", "CASE", "CAST", "CROSS", "CURRENT_DATE",
+		"CURRENT_TIME", "CURRENT_TIMESTAMP", "CURRENT_USER", "DESC", "DISTINCT", "ELSE", "END", "EXCEPT",
+		"EXISTS", "FALSE", "FETCH", "FOR", "FROM", "FULL", "GROUP", "HAVING", "ILIKE", "IN", "INNER",
+		"INTERSECT", "INTERVAL", "INTO", "IS", "JOIN", "LATERAL", "LEFT", "LIKE", "LIMIT", "LOCALTIME",
+		"LOCALTIMESTAMP", "MINUS", "NATURAL", "NOT", "NULL", "OFFSET", "ON", "OR", "ORDER", "OUTER", "OVER",
+		"QUALIFY", "RIGHT", "SELECT", "SESSION_USER", "SIMILAR", "SOME", "THEN", "TOP", "TRUE", "UNION",
+		"USING", "WHEN", "WHERE", "WINDOW", "WITH",
+	} {
+		reserved[k] = struct{}{}
+	}
+}
+
+func isReserved(v string) bool {
+	_, ok := reserved[strings.ToUpper(v)]
+	return ok
+}
@@ -0,0 +1,86 @@
+package lint
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/tmdc-io/tbls/config"
+)
+
+// Baseline is the recorded warnings. Warnings in the baseline are not reported
+type Baseline struct {
+	Warnings []*BaselineWarn `json:"warnings"`
+}
+
+// BaselineWarn is a recorded warning. The message is informational and is not used to match warnings
+type BaselineWarn struct {
+	Rule    string `json:"rule"`
+	Target  string `json:"target"`
+	Message string `json:"message"`
+}
+
+// NewBaseline return Baseline of warnings
+func NewBaseline(warns []config.RuleWarn) *Baseline {
+	b := &Baseline{
+		Warnings: []*BaselineWarn{},
+	}
+	for _, w := range warns {
+		b.Warnings = append(b.Warnings, &BaselineWarn{
+			Rule:    w.Rule,
+			Target:  w.Target,
+			Message: w.Message,
+		})
+	}
+	return b
+}
+
+// LoadBaseline load the baseline file
+func LoadBaseline(path string) (*Baseline, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	b := &Baseline{}
+	if err := json.Unmarshal(buf, b); err != nil {
+		return nil, errors.Wrapf(err, "invalid baseline file '%s'", path)
+	}
+	return b, nil
+}
+
+// Save save the baseline to the file
+func (b *Baseline) Save(path string) error {
+	buf, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.WriteFile(path, append(buf, '\n'), 0644); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// baselineKey is the key to match warnings with the baseline
+type baselineKey struct {
+	rule   string
+	target string
+}
+
+// Filter return warnings that are not in the baseline. Warnings are matched by the rule and the target
+// so that changes of messages ( e.g. counts in the message ) do not report recorded warnings again
+func (b *Baseline) Filter(warns []config.RuleWarn) []config.RuleWarn {
+	recorded := map[baselineKey]int{}
+	for _, bw := range b.Warnings {
+		recorded[baselineKey{rule: bw.Rule, target: bw.Target}]++
+	}
+	news := []config.RuleWarn{}
+	for _, w := range warns {
+		k := baselineKey{rule: w.Rule, target: w.Target}
+		if recorded[k] > 0 {
+			recorded[k]--
+			continue
+		}
+		news = append(news, w)
+	}
+	return news
+}
@@ -0,0 +1,104 @@
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/schema"
+)
+
+// Run check the schema with all lint rules of the config and return warnings with the rule name, the severity and the location.
+// Warnings suppressed by `tbls:ignore` markers in comments or `ignoreLint:` of `comments:` are excluded
+func Run(c *config.Config, s *schema.Schema) ([]config.RuleWarn, error) {
+	l := reflect.Indirect(reflect.ValueOf(c.Lint))
+	t := l.Type()
+	sups := suppressions(c, s)
+
+	warns := []config.RuleWarn{}
+	for i := 0; i < t.NumField(); i++ {
+		r, ok := l.Field(i).Interface().(config.Rule)
+		if !ok {
+			continue
+		}
+		name := ruleName(t.Field(i))
+		severity := config.SeverityError
+		if f := l.Field(i); f.Kind() == reflect.Struct {
+			if v := f.FieldByName("Severity"); v.IsValid() && v.String() != "" {
+				severity = v.String()
+			}
+		}
+		exclude := s.NormalizeTableNames(c.LintExclude)
+		var ws []config.RuleWarn
+		if rr, ok := r.(runner); ok {
+			var err error
+			ws, err = rr.Run(s, exclude)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			ws = r.Check(s, exclude)
+		}
+		for _, w := range ws {
+			if w.Rule == "" {
+				w.Rule = name
+			}
+			if w.Severity == "" {
+				w.Severity = severity
+			}
+			if !config.ValidSeverity(w.Severity) {
+				return nil, errors.Errorf("invalid severity '%s' of lint rule '%s'. severity should be one of error, warning or info", w.Severity, w.Rule)
+			}
+			w.Location = location(c, w.Location)
+			if suppressed(sups, w) {
+				continue
+			}
+			warns = append(warns, w)
+		}
+	}
+	return warns, nil
+}
+
+// runner is the interface of rules that return an error when they fail to check ( e.g. custom rules )
+type runner interface {
+	Run(s *schema.Schema, exclude []string) ([]config.RuleWarn, error)
+}
+
+// Errors return warnings of error severity
+func Errors(warns []config.RuleWarn) []config.RuleWarn {
+	errs := []config.RuleWarn{}
+	for _, w := range warns {
+		if w.Severity == config.SeverityError {
+			errs = append(errs, w)
+		}
+	}
+	return errs
+}
+
+// ruleName return the name of the rule in the config file
+func ruleName(f reflect.StructField) string {
+	name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// location return the location with the path of the document of the table ( or the schema )
+func location(c *config.Config, l *config.Location) *config.Location {
+	if l == nil {
+		l = &config.Location{}
+	}
+	if l.Path != "" {
+		return l
+	}
+	if l.Table == "" {
+		l.Path = filepath.ToSlash(filepath.Join(c.DocPath, "README.md"))
+		return l
+	}
+	l.Path = filepath.ToSlash(filepath.Join(c.DocPath, fmt.Sprintf("%s.md", l.Table)))
+	return l
+}
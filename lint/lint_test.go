@@ -0,0 +1,290 @@
+package lint
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/datasource"
+	"github.com/tmdc-io/tbls/schema"
+)
+
+func TestRun(t *testing.T) {
+	c := newTestConfig(t)
+	s, err := datasource.Analyze(config.DSN{URL: "json://../testdata/testdb.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Run(c, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []config.RuleWarn{
+		{Target: "public.logs", Message: "primary key required.", Rule: "requirePrimaryKey", Severity: "error", Location: &config.Location{Table: "public.logs", Path: "dbdoc/public.logs.md"}},
+		{Target: "public.CamelizeTable", Message: "primary key required.", Rule: "requirePrimaryKey", Severity: "error", Location: &config.Location{Table: "public.CamelizeTable", Path: "dbdoc/public.CamelizeTable.md"}},
+		{Target: "public.hyphen-table", Message: "primary key required.", Rule: "requirePrimaryKey", Severity: "error", Location: &config.Location{Table: "public.hyphen-table", Path: "dbdoc/public.hyphen-table.md"}},
+		{Target: "public.hyphen-table.hyphen-column", Message: "column name should be snake_case.", Rule: "columnNaming", Severity: "error", Location: &config.Location{Table: "public.hyphen-table", Object: "hyphen-column", Path: "dbdoc/public.hyphen-table.md"}},
+		{Target: "public.hyphen-table.CamelizeTableId", Message: "column name should be snake_case.", Rule: "columnNaming", Severity: "error", Location: &config.Location{Table: "public.hyphen-table", Object: "CamelizeTableId", Path: "dbdoc/public.hyphen-table.md"}},
+		{Target: "public.posts.labels", Message: "labels should be NOT NULL.", Rule: "notNullArray", Severity: "error", Location: &config.Location{Table: "public.posts", Object: "labels", Path: "dbdoc/public.posts.md"}},
+	}
+	if diff := cmp.Diff(got, want, nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"json", "lint_test.json.golden"},
+		{"sarif", "lint_test.sarif.golden"},
+		{"junit", "lint_test.junit.xml.golden"},
+		{"github", "lint_test.github.golden"},
+	}
+	c := newTestConfig(t)
+	s, err := datasource.Analyze(config.DSN{URL: "json://../testdata/testdb.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	warns, err := Run(c, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		buf := &bytes.Buffer{}
+		if err := Write(buf, tt.format, warns); err != nil {
+			t.Fatal(err)
+		}
+		want, _ := os.ReadFile(filepath.Join(testdataDir(), tt.want))
+		got := buf.String()
+		if got != string(want) {
+			t.Errorf("%s: got %v\nwant %v", tt.format, got, string(want))
+		}
+	}
+	if err := Write(&bytes.Buffer{}, "unknown", warns); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	c := newTestConfig(t)
+	c.Lint.RequirePrimaryKey.Severity = config.SeverityWarning
+	c.Lint.Custom[0].Severity = config.SeverityInfo
+	s, err := datasource.Analyze(config.DSN{URL: "json://../testdata/testdb.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	warns, err := Run(c, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, w := range warns {
+		got[w.Rule] = w.Severity
+	}
+	want := map[string]string{
+		"requirePrimaryKey": "warning",
+		"columnNaming":      "error",
+		"notNullArray":      "info",
+	}
+	if diff := cmp.Diff(got, want, nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+	if got := len(Errors(warns)); got != 2 {
+		t.Errorf("got %v\nwant %v", got, 2)
+	}
+
+	c.Lint.ColumnNaming.Severity = "fatal"
+	if _, err := Run(c, s); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	c := newTestConfig(t)
+	s, err := datasource.Analyze(config.DSN{URL: "json://../testdata/testdb.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	warns, err := Run(c, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "lint-baseline.json")
+	if err := NewBaseline(warns[:4]).Save(path); err != nil {
+		t.Fatal(err)
+	}
+	b, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := b.Filter(warns)
+	if diff := cmp.Diff(got, warns[4:], nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+
+	// messages are not used to match warnings
+	for _, bw := range b.Warnings {
+		bw.Message = "changed"
+	}
+	if diff := cmp.Diff(b.Filter(warns), warns[4:], nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+	if _, err := LoadBaseline(filepath.Join(t.TempDir(), "not_exist.json")); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestSuppress(t *testing.T) {
+	tests := []struct {
+		tableComments  map[string]string
+		columnComments map[[2]string]string
+		comments       []config.AdditionalComment
+		want           int
+	}{
+		{nil, nil, nil, 6},
+		{map[string]string{"public.logs": "audit log tbls:ignore requirePrimaryKey"}, nil, nil, 5},
+		{map[string]string{"public.logs": "tbls:ignore columnNaming"}, nil, nil, 6},
+		{map[string]string{"public.hyphen-table": "tbls:ignore"}, nil, nil, 3},
+		{map[string]string{"public.hyphen-table": "tbls:ignore requirePrimaryKey, columnNaming"}, nil, nil, 3},
+		{nil, map[[2]string]string{{"public.hyphen-table", "hyphen-column"}: "legacy tbls:ignore columnNaming"}, nil, 5},
+		{nil, nil, []config.AdditionalComment{
+			{Table: "public.hyphen-table", IgnoreLint: []config.IgnoreLint{{Rule: "columnNaming", Objects: []string{"Camelize*"}, Reason: "legacy"}}},
+		}, 5},
+		{nil, nil, []config.AdditionalComment{
+			{Table: "public.posts", IgnoreLint: []config.IgnoreLint{{Rule: "*"}}},
+			{Table: "public.logs", IgnoreLint: []config.IgnoreLint{{Rule: "requirePrimaryKey"}}},
+		}, 4},
+	}
+	for i, tt := range tests {
+		c := newTestConfig(t)
+		c.Comments = tt.comments
+		s, err := datasource.Analyze(config.DSN{URL: "json://../testdata/testdb.json"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, comment := range tt.tableComments {
+			tbl, err := s.FindTableByName(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tbl.Comment = comment
+		}
+		for name, comment := range tt.columnComments {
+			tbl, err := s.FindTableByName(name[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			col, err := tbl.FindColumnByName(name[1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			col.Comment = comment
+		}
+		warns, err := Run(c, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := len(warns); got != tt.want {
+			t.Errorf("[%d] got %v\nwant %v", i, got, tt.want)
+		}
+	}
+}
+
+func TestSuppressSameName(t *testing.T) {
+	c := newTestConfig(t)
+	tbl := &schema.Table{
+		Name: "users",
+		Indexes: []*schema.Index{
+			{Name: "users_pkey", Comment: "tbls:ignore requireIndexComment"},
+		},
+		Constraints: []*schema.Constraint{
+			{Name: "users_pkey", Type: "PRIMARY KEY"},
+		},
+	}
+	s := &schema.Schema{Tables: []*schema.Table{tbl}}
+	sups := suppressions(c, s)
+	w := config.RuleWarn{
+		Rule:     "requireIndexComment",
+		Location: &config.Location{Table: "users", Object: "users_pkey"},
+	}
+	if !suppressed(sups, w) {
+		t.Errorf("got not suppressed\nwant suppressed")
+	}
+}
+
+func TestSuppressWithoutTableTarget(t *testing.T) {
+	tests := []struct {
+		idComment string
+		comments  []config.AdditionalComment
+		want      []string
+	}{
+		{"", nil, []string{"id", "posts(user_id) -> users(id)"}},
+		{"tbls:ignore inconsistentColumnType", nil, []string{"posts(user_id) -> users(id)"}},
+		{"", []config.AdditionalComment{
+			{Table: "posts", IgnoreLint: []config.IgnoreLint{{Rule: "no relation"}}},
+		}, []string{"id"}},
+	}
+	for i, tt := range tests {
+		c, err := config.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.DocPath = "dbdoc"
+		c.Comments = tt.comments
+		c.Lint.InconsistentColumnType = config.InconsistentColumnType{Enabled: true}
+		c.Lint.Custom = config.CustomRules{{Enabled: true, Name: "no relation", Target: "relation", Assert: "false"}}
+		users := &schema.Table{Name: "users", Columns: []*schema.Column{{Name: "id", Type: "bigint"}}}
+		posts := &schema.Table{Name: "posts", Columns: []*schema.Column{{Name: "id", Type: "int", Comment: tt.idComment}, {Name: "user_id", Type: "bigint"}}}
+		s := &schema.Schema{
+			Tables: []*schema.Table{users, posts},
+			Relations: []*schema.Relation{
+				{Table: posts, Columns: []*schema.Column{posts.Columns[1]}, ParentTable: users, ParentColumns: []*schema.Column{users.Columns[0]}},
+			},
+		}
+		warns, err := Run(c, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, w := range warns {
+			got = append(got, w.Target)
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("[%d] %s", i, diff)
+		}
+	}
+}
+
+func newTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+	c, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.DocPath = "dbdoc"
+	c.LintExclude = []string{"comment_stars"}
+	c.Lint.RequirePrimaryKey = config.RequirePrimaryKey{Enabled: true}
+	c.Lint.ColumnNaming = config.ColumnNaming{Enabled: true, Style: "snake_case"}
+	c.Lint.Custom = config.CustomRules{
+		{
+			Enabled: true,
+			Name:    "notNullArray",
+			Target:  "column",
+			Filter:  "Column.Type == 'array'",
+			Assert:  "!Column.Nullable",
+			Message: "{{ .Column.Name }} should be NOT NULL.",
+		},
+	}
+	return c
+}
+
+func testdataDir() string {
+	wd, _ := os.Getwd()
+	dir, _ := filepath.Abs(filepath.Join(filepath.Dir(wd), "testdata"))
+	return dir
+}
@@ -0,0 +1,277 @@
+package lint
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/labstack/gommon/color"
+	"github.com/pkg/errors"
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/version"
+)
+
+// Formats of Write
+var Formats = []string{"text", "json", "sarif", "junit", "github"}
+
+// Write write warnings in the format
+func Write(wr io.Writer, format string, warns []config.RuleWarn) error {
+	switch format {
+	case "text", "":
+		return writeText(wr, warns)
+	case "json":
+		return writeJSON(wr, warns)
+	case "sarif":
+		return writeSARIF(wr, warns)
+	case "junit":
+		return writeJUnit(wr, warns)
+	case "github":
+		return writeGitHub(wr, warns)
+	default:
+		return errors.Errorf("unsupported format '%s'. supported formats are %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+func writeText(wr io.Writer, warns []config.RuleWarn) error {
+	if len(warns) == 0 {
+		return nil
+	}
+	for _, w := range warns {
+		msg := w.Message
+		if w.Severity != "" && w.Severity != config.SeverityError {
+			msg = fmt.Sprintf("%s (%s)", msg, w.Severity)
+		}
+		if _, err := fmt.Fprintf(wr, "%s%s\n", color.Cyan(w.Target), color.White(fmt.Sprintf(": %s", msg), color.B)); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if _, err := fmt.Fprintln(wr, color.White(fmt.Sprintf("\n%d detected", len(warns)), color.B)); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func writeJSON(wr io.Writer, warns []config.RuleWarn) error {
+	encoder := json.NewEncoder(wr)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(warns); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// SARIF ( https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html )
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Version        string      `json:"version"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+func writeSARIF(wr io.Writer, warns []config.RuleWarn) error {
+	rules := []sarifRule{}
+	results := []sarifResult{}
+	seen := map[string]struct{}{}
+	for _, w := range warns {
+		if _, ok := seen[w.Rule]; !ok {
+			seen[w.Rule] = struct{}{}
+			rules = append(rules, sarifRule{ID: w.Rule, ShortDescription: sarifMessage{Text: w.Rule}})
+		}
+		loc := sarifLocation{
+			LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: w.Target}},
+		}
+		if w.Location != nil && w.Location.Path != "" {
+			loc.PhysicalLocation = &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: w.Location.Path}}
+		}
+		results = append(results, sarifResult{
+			RuleID:    w.Rule,
+			Level:     sarifLevel(w.Severity),
+			Message:   sarifMessage{Text: fmt.Sprintf("%s: %s", w.Target, w.Message)},
+			Locations: []sarifLocation{loc},
+		})
+	}
+	l := sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "tbls",
+				InformationURI: "https://github.com/tmdc-io/tbls",
+				Version:        version.Version,
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+	encoder := json.NewEncoder(wr)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(l); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func sarifLevel(severity string) string {
+	switch severity {
+	case config.SeverityWarning:
+		return "warning"
+	case config.SeverityInfo:
+		return "note"
+	default:
+		return "error"
+	}
+}
+
+// JUnit XML. A test suite per rule and a failed test case per warning
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string       `xml:"name,attr"`
+	ClassName string       `xml:"classname,attr"`
+	File      string       `xml:"file,attr,omitempty"`
+	Failure   junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func writeJUnit(wr io.Writer, warns []config.RuleWarn) error {
+	suites := junitTestSuites{
+		Name:       "tbls lint",
+		Tests:      len(warns),
+		Failures:   len(warns),
+		TestSuites: []junitTestSuite{},
+	}
+	idx := map[string]int{}
+	for _, w := range warns {
+		i, ok := idx[w.Rule]
+		if !ok {
+			i = len(suites.TestSuites)
+			idx[w.Rule] = i
+			suites.TestSuites = append(suites.TestSuites, junitTestSuite{Name: w.Rule})
+		}
+		tc := junitTestCase{
+			Name:      w.Target,
+			ClassName: w.Rule,
+			Failure: junitFailure{
+				Message: w.Message,
+				Type:    w.Severity,
+				Text:    fmt.Sprintf("%s: %s", w.Target, w.Message),
+			},
+		}
+		if w.Location != nil {
+			tc.File = w.Location.Path
+		}
+		suites.TestSuites[i].Tests++
+		suites.TestSuites[i].Failures++
+		suites.TestSuites[i].TestCases = append(suites.TestSuites[i].TestCases, tc)
+	}
+	if _, err := io.WriteString(wr, xml.Header); err != nil {
+		return errors.WithStack(err)
+	}
+	encoder := xml.NewEncoder(wr)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := io.WriteString(wr, "\n"); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// GitHub Actions workflow commands ( https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions )
+func writeGitHub(wr io.Writer, warns []config.RuleWarn) error {
+	for _, w := range warns {
+		level := "error"
+		switch w.Severity {
+		case config.SeverityWarning:
+			level = "warning"
+		case config.SeverityInfo:
+			level = "notice"
+		}
+		props := []string{}
+		if w.Location != nil && w.Location.Path != "" {
+			props = append(props, fmt.Sprintf("file=%s", escapeGitHubProperty(w.Location.Path)))
+		}
+		props = append(props, fmt.Sprintf("title=%s", escapeGitHubProperty(fmt.Sprintf("tbls lint: %s", w.Rule))))
+		if _, err := fmt.Fprintf(wr, "::%s %s::%s\n", level, strings.Join(props, ","), escapeGitHubData(fmt.Sprintf("%s: %s", w.Target, w.Message))); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,138 @@
+package lint
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/minio/pkg/wildcard"
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/schema"
+)
+
+// ignoreMarkerRe is the marker of lint suppression in comments. e.g. `tbls:ignore requireColumnComment, columnNaming`.
+// The marker without rule names suppresses all rules
+var ignoreMarkerRe = regexp.MustCompile(`tbls:ignore(?:[ \t]+([\w\-.]+(?:[ \t]*,[ \t]*[\w\-.]+)*))?`)
+
+// suppression is the suppressed rules of the table and the objects of the table
+type suppression struct {
+	table   []string
+	objects map[string][]string
+}
+
+// suppressions return suppressed rules by table name from comments of the schema and `ignoreLint:` of `comments:`
+func suppressions(c *config.Config, s *schema.Schema) map[string]*suppression {
+	sups := map[string]*suppression{}
+	get := func(t string) *suppression {
+		if _, ok := sups[t]; !ok {
+			sups[t] = &suppression{
+				table:   []string{},
+				objects: map[string][]string{},
+			}
+		}
+		return sups[t]
+	}
+	for _, t := range s.Tables {
+		if rules, ok := ignoredRules(t.Comment); ok {
+			get(t.Name).table = append(get(t.Name).table, rules...)
+		}
+		// objects of different kinds can have the same name ( e.g. a primary key and its index )
+		add := func(name, comment string) {
+			if rules, ok := ignoredRules(comment); ok {
+				get(t.Name).objects[name] = append(get(t.Name).objects[name], rules...)
+			}
+		}
+		for _, c := range t.Columns {
+			add(c.Name, c.Comment)
+		}
+		for _, i := range t.Indexes {
+			add(i.Name, i.Comment)
+		}
+		for _, c := range t.Constraints {
+			add(c.Name, c.Comment)
+		}
+		for _, tr := range t.Triggers {
+			add(tr.Name, tr.Comment)
+		}
+	}
+	for _, ac := range c.Comments {
+		if len(ac.IgnoreLint) == 0 {
+			continue
+		}
+		t, err := s.FindTableByName(ac.Table)
+		if err != nil {
+			continue
+		}
+		for _, il := range ac.IgnoreLint {
+			if len(il.Objects) == 0 {
+				get(t.Name).table = append(get(t.Name).table, il.Rule)
+				continue
+			}
+			for _, o := range il.Objects {
+				get(t.Name).objects[o] = append(get(t.Name).objects[o], il.Rule)
+			}
+		}
+	}
+	return sups
+}
+
+// ignoredRules return rules in the marker of the comment. `*` means all rules
+func ignoredRules(comment string) ([]string, bool) {
+	rules := []string{}
+	found := false
+	for _, m := range ignoreMarkerRe.FindAllStringSubmatch(comment, -1) {
+		found = true
+		if m[1] == "" {
+			rules = append(rules, "*")
+			continue
+		}
+		for _, r := range strings.Split(m[1], ",") {
+			rules = append(rules, strings.TrimSpace(r))
+		}
+	}
+	return rules, found
+}
+
+// suppressed return true if the rule of the warning is suppressed for the table or the object
+// The warning of the object in several tables ( e.g. inconsistentColumnType ) is suppressed if it is suppressed in one of the tables
+func suppressed(sups map[string]*suppression, w config.RuleWarn) bool {
+	if w.Location == nil {
+		return false
+	}
+	tables := w.Location.Tables
+	if w.Location.Table != "" {
+		tables = []string{w.Location.Table}
+	}
+	for _, t := range tables {
+		if suppressedIn(sups[t], w.Rule, w.Location.Object) {
+			return true
+		}
+	}
+	return false
+}
+
+func suppressedIn(sup *suppression, rule, object string) bool {
+	if sup == nil {
+		return false
+	}
+	if matchRule(sup.table, rule) {
+		return true
+	}
+	if object == "" {
+		return false
+	}
+	for o, rules := range sup.objects {
+		if wildcard.MatchSimple(o, object) && matchRule(rules, rule) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchRule(rules []string, rule string) bool {
+	for _, r := range rules {
+		if wildcard.MatchSimple(r, rule) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/schema"
+	"github.com/tmdc-io/tbls/version"
+)
+
+// FileName is the file name of the manifest in the document directory
+const FileName = ".tbls.manifest.json"
+
+// Manifest is the content hashes and generated files of the document
+type Manifest struct {
+	Schema *Entry            `json:"schema"`
+	Tables map[string]*Entry `json:"tables"`
+}
+
+// Entry is the content hash and generated files of a page
+type Entry struct {
+	Hash  string   `json:"hash"`
+	Files []string `json:"files"`
+}
+
+// New return the manifest of the document generated from the schema
+func New(s *schema.Schema, c *config.Config) (*Manifest, error) {
+	ch, err := configHash(c)
+	if err != nil {
+		return nil, err
+	}
+	h, err := hash(ch, s)
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{
+		Schema: &Entry{
+			Hash:  h,
+			Files: SchemaFiles(c),
+		},
+		Tables: map[string]*Entry{},
+	}
+	for _, t := range s.Tables {
+		h, err := tableHash(ch, s, t, c)
+		if err != nil {
+			return nil, err
+		}
+		m.Tables[t.Name] = &Entry{
+			Hash:  h,
+			Files: TableFiles(t, c),
+		}
+	}
+	return m, nil
+}
+
+// Load return the manifest in the document directory. If it does not exist, return an empty manifest.
+func Load(dir string) (*Manifest, error) {
+	m := &Manifest{
+		Tables: map[string]*Entry{},
+	}
+	b, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, errors.Wrapf(err, "invalid manifest %s", FileName)
+	}
+	if m.Tables == nil {
+		m.Tables = map[string]*Entry{}
+	}
+	return m, nil
+}
+
+// Save write the manifest to the document directory
+func (m *Manifest) Save(dir string) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, FileName), append(b, '\n'), 0644); err != nil { // #nosec
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// SchemaChanged return true if the schema pages should be regenerated
+func (m *Manifest) SchemaChanged(prev *Manifest, dir string) bool {
+	return changed(m.Schema, prev.Schema, dir)
+}
+
+// ChangedTables return tables whose pages should be regenerated
+func (m *Manifest) ChangedTables(s *schema.Schema, prev *Manifest, dir string) []*schema.Table {
+	tables := []*schema.Table{}
+	for _, t := range s.Tables {
+		if changed(m.Tables[t.Name], prev.Tables[t.Name], dir) {
+			tables = append(tables, t)
+		}
+	}
+	return tables
+}
+
+// StaleFiles return files generated previously but no longer generated ( e.g. pages of dropped tables )
+func (m *Manifest) StaleFiles(prev *Manifest) []string {
+	current := map[string]struct{}{}
+	for _, e := range m.entries() {
+		for _, f := range e.Files {
+			current[f] = struct{}{}
+		}
+	}
+	stale := []string{}
+	for _, e := range prev.entries() {
+		for _, f := range e.Files {
+			if _, ok := current[f]; ok {
+				continue
+			}
+			current[f] = struct{}{}
+			stale = append(stale, f)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// SchemaFiles return files of the schema pages
+func SchemaFiles(c *config.Config) []string {
+	files := []string{"README.md"}
+	if c.ER.Skip {
+		return files
+	}
+	files = append(files, fmt.Sprintf("schema.%s", c.ER.Format))
+	if c.ER.HTML && c.ER.Format == "svg" {
+		files = append(files, "schema.html")
+	}
+	return files
+}
+
+// TableFiles return files of the table pages
+func TableFiles(t *schema.Table, c *config.Config) []string {
+	files := []string{fmt.Sprintf("%s.md", t.Name)}
+	if c.ER.Skip {
+		return files
+	}
+	files = append(files, fmt.Sprintf("%s.%s", t.Name, c.ER.Format))
+	if t.HasLineage() && c.ER.Format != "d2" {
+		files = append(files, fmt.Sprintf("%s.lineage.%s", t.Name, c.ER.Format))
+	}
+	return files
+}
+
+func (m *Manifest) entries() []*Entry {
+	entries := []*Entry{}
+	if m.Schema != nil {
+		entries = append(entries, m.Schema)
+	}
+	for _, e := range m.Tables {
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func changed(e, prev *Entry, dir string) bool {
+	if prev == nil || e.Hash != prev.Hash {
+		return true
+	}
+	for _, f := range e.Files {
+		if _, err := os.Lstat(filepath.Join(dir, f)); err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// configHash return hash of the config and templates that affect the document
+func configHash(c *config.Config) (string, error) {
+	templates := map[string]string{}
+	for _, p := range c.Templates.Paths() {
+		b, err := os.ReadFile(filepath.Clean(p))
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		templates[p] = string(b)
+	}
+	return hash(version.Version, c.Name, c.Desc, c.Labels, c.Format, c.ER, &c.MergedDict, c.BaseUrl, templates)
+}
+
+// tableHash return hash of the table, the tables and relations drawn in the ER diagram of the table,
+// the views that depend on the table and the upstream of the column lineage.
+func tableHash(ch string, s *schema.Schema, t *schema.Table, c *config.Config) (string, error) {
+	direction, err := schema.ParseDirection(c.ER.Direction)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	tables, relations := t.CollectRelatedTables(*c.ER.Distance, direction)
+	usedBy := []string{}
+	for _, v := range s.ReferencedBy(t) {
+		usedBy = append(usedBy, v.Name)
+	}
+	return hash(ch, tables, relations, usedBy, lineageTables(t, map[*schema.Table]struct{}{t: struct{}{}}))
+}
+
+func lineageTables(t *schema.Table, visited map[*schema.Table]struct{}) []*schema.Table {
+	tables := []*schema.Table{}
+	for _, c := range t.Columns {
+		for _, l := range c.Lineage {
+			if _, ok := visited[l.Table]; ok {
+				continue
+			}
+			visited[l.Table] = struct{}{}
+			tables = append(tables, l.Table)
+			tables = append(tables, lineageTables(l.Table, visited)...)
+		}
+	}
+	return tables
+}
+
+func hash(v ...interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,163 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/datasource"
+	"github.com/tmdc-io/tbls/schema"
+)
+
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+	c, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := analyze(t)
+
+	prev, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := New(s, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.SchemaChanged(prev, dir) {
+		t.Error("got unchanged\nwant changed")
+	}
+	if got, want := len(m.ChangedTables(s, prev, dir)), len(s.Tables); got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	for _, e := range m.entries() {
+		for _, f := range e.Files {
+			if err := os.WriteFile(filepath.Join(dir, f), []byte{}, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := m.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// no change
+	prev, err = Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s = analyze(t)
+	m, err = New(s, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.SchemaChanged(prev, dir) {
+		t.Error("got changed\nwant unchanged")
+	}
+	if got := m.ChangedTables(s, prev, dir); len(got) != 0 {
+		t.Errorf("got %v\nwant %v", tableNames(got), "")
+	}
+
+	// change a table and the tables related to it are changed
+	uo, err := s.FindTableByName("public.user_options")
+	if err != nil {
+		t.Fatal(err)
+	}
+	uo.Comment = "changed"
+	m, err = New(s, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.SchemaChanged(prev, dir) {
+		t.Error("got unchanged\nwant changed")
+	}
+	if got, want := tableNames(m.ChangedTables(s, prev, dir)), "public.users,public.user_options"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+
+	// removed file is regenerated
+	if err := os.Remove(filepath.Join(dir, "public.posts.md")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tableNames(m.ChangedTables(s, prev, dir)), "public.users,public.user_options,public.posts"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func TestConfigHash(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func(c *config.Config, path string)
+	}{
+		{"md", func(c *config.Config, path string) { c.Templates.MD.Table = path }},
+		{"dot", func(c *config.Config, path string) { c.Templates.Dot.Lineage = path }},
+		{"puml", func(c *config.Config, path string) { c.Templates.PUML.Schema = path }},
+		{"d2", func(c *config.Config, path string) { c.Templates.D2.Table = path }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "template.tmpl")
+			if err := os.WriteFile(path, []byte("before"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			c, err := config.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.set(c, path)
+			before, err := configHash(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte("after"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			after, err := configHash(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if before == after {
+				t.Error("got unchanged\nwant changed")
+			}
+		})
+	}
+}
+
+func TestStaleFiles(t *testing.T) {
+	prev := &Manifest{
+		Schema: &Entry{Files: []string{"README.md", "schema.svg"}},
+		Tables: map[string]*Entry{
+			"users":   &Entry{Files: []string{"users.md", "users.svg"}},
+			"dropped": &Entry{Files: []string{"dropped.md", "dropped.svg"}},
+		},
+	}
+	m := &Manifest{
+		Schema: &Entry{Files: []string{"README.md", "schema.png"}},
+		Tables: map[string]*Entry{
+			"users": &Entry{Files: []string{"users.md", "users.png"}},
+		},
+	}
+	if got, want := strings.Join(m.StaleFiles(prev), ","), "dropped.md,dropped.svg,schema.svg,users.svg"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func analyze(t *testing.T) *schema.Schema {
+	t.Helper()
+	s, err := datasource.Analyze(config.DSN{URL: "json://../testdata/testdb.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func tableNames(tables []*schema.Table) string {
+	names := []string{}
+	for _, t := range tables {
+		names = append(names, t.Name)
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,544 @@
+package migrate
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tmdc-io/tbls/output/ddl"
+	"github.com/tmdc-io/tbls/schema"
+)
+
+// Plan is the ordered statements that turn a schema into another schema
+type Plan struct {
+	Dialect ddl.Dialect `json:"dialect"`
+	Steps   []*Step     `json:"steps"`
+}
+
+// Step is a statement of the plan
+type Step struct {
+	SQL         string `json:"sql"`
+	Destructive bool   `json:"destructive"`
+	Warning     string `json:"warning,omitempty"`
+}
+
+// phases of the plan. Foreign keys, indexes and constraints are dropped before tables and columns are changed,
+// and added after tables and columns are created.
+const (
+	phaseDropForeignKeys = iota
+	phaseDropIndexes
+	phaseDropConstraints
+	phaseCreateTables
+	phaseAddColumns
+	phaseAlterColumns
+	phaseDropColumns
+	phaseAddConstraints
+	phaseCreateIndexes
+	phaseAddForeignKeys
+	phaseDropTables
+	phaseComments
+	numPhases
+)
+
+type planner struct {
+	d       ddl.Dialect
+	phases  [numPhases][]*Step
+	rebuilt bool
+}
+
+// New return the plan that turns the schema from into the schema to
+func New(from, to *schema.Schema, d ddl.Dialect) *Plan {
+	p := &planner{d: d}
+
+	for _, t := range tables(to) {
+		ft, err := from.FindTableByName(t.Name)
+		if err != nil || !ddl.IsTable(ft) {
+			p.createTable(t, to)
+			continue
+		}
+		p.alterTable(ft, t, from, to)
+	}
+	dropped := []*schema.Table{}
+	for _, t := range tables(from) {
+		if tt, err := to.FindTableByName(t.Name); err == nil && ddl.IsTable(tt) {
+			continue
+		}
+		dropped = append(dropped, t)
+	}
+	p.dropTables(dropped, from)
+
+	plan := &Plan{
+		Dialect: d,
+		Steps:   []*Step{},
+	}
+	for _, steps := range p.phases {
+		plan.Steps = append(plan.Steps, steps...)
+	}
+	if p.rebuilt {
+		// https://www.sqlite.org/lang_altertable.html#otheralter
+		steps := []*Step{{SQL: "PRAGMA foreign_keys=OFF;"}, {SQL: "BEGIN;"}}
+		steps = append(steps, plan.Steps...)
+		steps = append(steps,
+			&Step{SQL: "PRAGMA foreign_key_check;", Warning: "roll back if foreign key violations are reported"},
+			&Step{SQL: "COMMIT;"},
+			&Step{SQL: "PRAGMA foreign_keys=ON;"},
+		)
+		plan.Steps = steps
+	}
+	return plan
+}
+
+// Destructive return the steps that may lose data
+func (p *Plan) Destructive() []*Step {
+	steps := []*Step{}
+	for _, s := range p.Steps {
+		if s.Destructive {
+			steps = append(steps, s)
+		}
+	}
+	return steps
+}
+
+// Write write the plan as SQL. Warnings are written as SQL comments.
+func (p *Plan) Write(wr io.Writer) error {
+	b := &strings.Builder{}
+	if len(p.Steps) == 0 {
+		b.WriteString("-- no changes\n")
+	}
+	for _, s := range p.Steps {
+		if s.Warning != "" {
+			level := "WARNING"
+			if s.Destructive {
+				level = "DESTRUCTIVE"
+			}
+			fmt.Fprintf(b, "-- %s: %s\n", level, s.Warning)
+		}
+		fmt.Fprintf(b, "%s\n", s.SQL)
+	}
+	if n := len(p.Destructive()); n > 0 {
+		fmt.Fprintf(b, "-- %d destructive step(s)\n", n)
+	}
+	if _, err := io.WriteString(wr, b.String()); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (p *planner) add(phase int, s *Step) {
+	p.phases[phase] = append(p.phases[phase], s)
+}
+
+// dropTables add steps that drop the tables. Child tables are dropped before their parent tables,
+// and foreign keys of cyclic references are dropped first ( except SQLite, which can not drop foreign keys ).
+func (p *planner) dropTables(dropped []*schema.Table, s *schema.Schema) {
+	d := p.d
+	isDropped := map[*schema.Table]bool{}
+	for _, t := range dropped {
+		isDropped[t] = true
+	}
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := map[*schema.Table]int{}
+	var visit func(t *schema.Table)
+	visit = func(t *schema.Table) {
+		state[t] = visiting
+		for _, r := range s.Relations {
+			if r.Virtual || r.ParentTable != t || r.Table == t || !isDropped[r.Table] {
+				continue
+			}
+			switch state[r.Table] {
+			case 0:
+				visit(r.Table)
+			case visiting:
+				if d != ddl.SQLite {
+					sql := fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", d.TableName(r.Table.Name), d.Quote(ddl.ForeignKeyName(r)))
+					if d == ddl.MySQL {
+						sql = fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s;", d.TableName(r.Table.Name), d.Quote(ddl.ForeignKeyName(r)))
+					}
+					p.add(phaseDropForeignKeys, &Step{SQL: sql})
+				}
+			}
+		}
+		state[t] = visited
+		p.add(phaseDropTables, &Step{
+			SQL:         fmt.Sprintf("DROP TABLE %s;", d.TableName(t.Name)),
+			Destructive: true,
+			Warning:     fmt.Sprintf("drop table %s", t.Name),
+		})
+	}
+	for _, t := range dropped {
+		if state[t] == 0 {
+			visit(t)
+		}
+	}
+}
+
+func (p *planner) createTable(t *schema.Table, s *schema.Schema) {
+	d := p.d
+	inline := []*schema.Relation{}
+	for _, r := range foreignKeys(t, s) {
+		if d == ddl.SQLite {
+			inline = append(inline, r)
+			continue
+		}
+		p.add(phaseAddForeignKeys, &Step{SQL: fmt.Sprintf("ALTER TABLE %s ADD %s;", d.TableName(t.Name), d.ForeignKeyDef(r))})
+	}
+	p.add(phaseCreateTables, &Step{SQL: d.CreateTable(t, inline)})
+	for _, i := range ddl.Indexes(t) {
+		p.add(phaseCreateIndexes, &Step{SQL: d.CreateIndex(t, i)})
+	}
+	for _, c := range d.Comments(t) {
+		if d == ddl.SQLite {
+			continue
+		}
+		p.add(phaseComments, &Step{SQL: c})
+	}
+}
+
+func (p *planner) alterTable(from, to *schema.Table, fs, ts *schema.Schema) {
+	d := p.d
+	tn := d.TableName(to.Name)
+	rebuild := false
+	rebuildSteps := []*Step{}
+
+	// foreign keys
+	ffks := map[string]*schema.Relation{}
+	for _, r := range foreignKeys(from, fs) {
+		ffks[ddl.ForeignKeyName(r)] = r
+	}
+	tfks := map[string]*schema.Relation{}
+	for _, r := range foreignKeys(to, ts) {
+		tfks[ddl.ForeignKeyName(r)] = r
+	}
+	for _, r := range foreignKeys(from, fs) {
+		name := ddl.ForeignKeyName(r)
+		if tr, ok := tfks[name]; ok && d.ForeignKeyDef(tr) == d.ForeignKeyDef(r) {
+			continue
+		}
+		if d == ddl.SQLite {
+			rebuild = true
+			continue
+		}
+		sql := fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", tn, d.Quote(name))
+		if d == ddl.MySQL {
+			sql = fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s;", tn, d.Quote(name))
+		}
+		p.add(phaseDropForeignKeys, &Step{SQL: sql})
+	}
+	for _, r := range foreignKeys(to, ts) {
+		name := ddl.ForeignKeyName(r)
+		if fr, ok := ffks[name]; ok && d.ForeignKeyDef(fr) == d.ForeignKeyDef(r) {
+			continue
+		}
+		if d == ddl.SQLite {
+			rebuild = true
+			continue
+		}
+		p.add(phaseAddForeignKeys, &Step{SQL: fmt.Sprintf("ALTER TABLE %s ADD %s;", tn, d.ForeignKeyDef(r))})
+	}
+
+	// primary key
+	fpk, tpk := ddl.PrimaryKey(from), ddl.PrimaryKey(to)
+	if primaryKeyColumns(fpk) != primaryKeyColumns(tpk) {
+		if d == ddl.SQLite {
+			rebuild = true
+		} else {
+			if fpk != nil {
+				sql := fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", tn, d.Quote(fpk.Name))
+				if d == ddl.MySQL {
+					sql = fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY;", tn)
+				}
+				p.add(phaseDropConstraints, &Step{SQL: sql})
+			}
+			if tpk != nil {
+				if def, ok := d.ConstraintDef(tpk); ok {
+					p.add(phaseAddConstraints, &Step{SQL: fmt.Sprintf("ALTER TABLE %s ADD %s;", tn, def)})
+				}
+			}
+		}
+	}
+
+	// unique and check constraints
+	fcs := constraints(d, from)
+	tcs := constraints(d, to)
+	for _, c := range from.Constraints {
+		def, ok := fcs[c.Name]
+		if !ok || tcs[c.Name] == def {
+			continue
+		}
+		if d == ddl.SQLite {
+			rebuild = true
+			continue
+		}
+		sql := fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", tn, d.Quote(c.Name))
+		if d == ddl.MySQL {
+			if strings.Contains(def, "CHECK") {
+				sql = fmt.Sprintf("ALTER TABLE %s DROP CHECK %s;", tn, d.Quote(c.Name))
+			} else {
+				sql = fmt.Sprintf("ALTER TABLE %s DROP INDEX %s;", tn, d.Quote(c.Name))
+			}
+		}
+		p.add(phaseDropConstraints, &Step{SQL: sql})
+	}
+	for _, c := range to.Constraints {
+		def, ok := tcs[c.Name]
+		if !ok || fcs[c.Name] == def {
+			continue
+		}
+		if d == ddl.SQLite {
+			rebuild = true
+			continue
+		}
+		p.add(phaseAddConstraints, &Step{SQL: fmt.Sprintf("ALTER TABLE %s ADD %s;", tn, def)})
+	}
+
+	// columns
+	columnSteps := []struct {
+		phase int
+		step  *Step
+	}{}
+	addColumnStep := func(phase int, s *Step) {
+		columnSteps = append(columnSteps, struct {
+			phase int
+			step  *Step
+		}{phase, s})
+	}
+	for _, c := range to.Columns {
+		fc, err := from.FindColumnByName(c.Name)
+		if err != nil {
+			s := &Step{SQL: fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", tn, d.ColumnDef(c))}
+			if _, ok := d.DefaultValue(c); !c.Nullable && !ok && !ddl.AutoIncrement(c) {
+				s.Warning = fmt.Sprintf("add NOT NULL column %s.%s without default fails if the table has rows", to.Name, c.Name)
+			}
+			addColumnStep(phaseAddColumns, s)
+			if d == ddl.Postgres && c.Comment != "" {
+				addColumnStep(phaseComments, &Step{SQL: d.ColumnComment(to, c)})
+			}
+			continue
+		}
+		steps := p.alterColumn(to, fc, c)
+		if len(steps) > 0 && d == ddl.SQLite {
+			rebuild = true
+			rebuildSteps = append(rebuildSteps, steps...)
+			continue
+		}
+		for _, s := range steps {
+			phase := phaseAlterColumns
+			if strings.HasPrefix(s.SQL, "COMMENT ON") {
+				phase = phaseComments
+			}
+			addColumnStep(phase, s)
+		}
+	}
+	for _, c := range from.Columns {
+		if _, err := to.FindColumnByName(c.Name); err == nil {
+			continue
+		}
+		addColumnStep(phaseDropColumns, &Step{
+			SQL:         fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", tn, d.Quote(c.Name)),
+			Destructive: true,
+			Warning:     fmt.Sprintf("drop column %s.%s", from.Name, c.Name),
+		})
+	}
+
+	if rebuild {
+		p.rebuildTable(from, to, ts, rebuildSteps)
+		return
+	}
+	for _, cs := range columnSteps {
+		p.add(cs.phase, cs.step)
+	}
+
+	// indexes
+	fis := indexes(d, from)
+	tis := indexes(d, to)
+	for _, i := range ddl.Indexes(from) {
+		if tis[i.Name] == fis[i.Name] {
+			continue
+		}
+		p.add(phaseDropIndexes, &Step{SQL: d.DropIndex(from, i)})
+	}
+	for _, i := range ddl.Indexes(to) {
+		if fis[i.Name] == tis[i.Name] {
+			continue
+		}
+		p.add(phaseCreateIndexes, &Step{SQL: d.CreateIndex(to, i)})
+	}
+
+	// table comment
+	if from.Comment != to.Comment {
+		switch d {
+		case ddl.Postgres:
+			v := "NULL"
+			if to.Comment != "" {
+				v = d.QuoteString(to.Comment)
+			}
+			p.add(phaseComments, &Step{SQL: fmt.Sprintf("COMMENT ON TABLE %s IS %s;", tn, v)})
+		case ddl.MySQL:
+			p.add(phaseComments, &Step{SQL: fmt.Sprintf("ALTER TABLE %s COMMENT=%s;", tn, d.QuoteString(to.Comment))})
+		}
+	}
+}
+
+// alterColumn return steps that change the column
+func (p *planner) alterColumn(t *schema.Table, from, to *schema.Column) []*Step {
+	d := p.d
+	tn := d.TableName(t.Name)
+	cn := d.Quote(to.Name)
+	ftyp, ttyp := d.ConvertType(from.Type), d.ConvertType(to.Type)
+	fdef, fok := d.DefaultValue(from)
+	tdef, tok := d.DefaultValue(to)
+	typeChanged := ftyp != ttyp
+	nullChanged := from.Nullable != to.Nullable
+	defaultChanged := fok != tok || fdef != tdef
+	commentChanged := from.Comment != to.Comment
+
+	steps := []*Step{}
+	typeStep := func(s *Step) *Step {
+		if typeChanged {
+			s.Destructive = true
+			s.Warning = fmt.Sprintf("change type of %s.%s from %s to %s may lose data or fail", t.Name, to.Name, ftyp, ttyp)
+		} else if nullChanged && !to.Nullable {
+			s.Warning = fmt.Sprintf("set NOT NULL to %s.%s fails if the column has NULL", t.Name, to.Name)
+		}
+		return s
+	}
+	switch d {
+	case ddl.MySQL:
+		if typeChanged || nullChanged || defaultChanged || commentChanged {
+			steps = append(steps, typeStep(&Step{SQL: fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;", tn, d.ColumnDef(to))}))
+		}
+	case ddl.SQLite:
+		if typeChanged || nullChanged || defaultChanged {
+			steps = append(steps, typeStep(&Step{}))
+		}
+	default:
+		if typeChanged {
+			steps = append(steps, typeStep(&Step{SQL: fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s;", tn, cn, ttyp, cn, ttyp)}))
+		}
+		if nullChanged {
+			if to.Nullable {
+				steps = append(steps, &Step{SQL: fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;", tn, cn)})
+			} else {
+				typeChanged = false
+				steps = append(steps, typeStep(&Step{SQL: fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", tn, cn)}))
+			}
+		}
+		if defaultChanged {
+			if tok {
+				steps = append(steps, &Step{SQL: fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;", tn, cn, tdef)})
+			} else {
+				steps = append(steps, &Step{SQL: fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;", tn, cn)})
+			}
+		}
+		if commentChanged {
+			steps = append(steps, &Step{SQL: d.ColumnComment(t, to)})
+		}
+	}
+	return steps
+}
+
+// rebuildTable add steps that rebuild the table ( SQLite can not alter columns and constraints ).
+// Warnings of column changes are carried to the rebuild step.
+// The plan is wrapped in a transaction with foreign keys disabled, so that the table can be rebuilt even if other tables reference it.
+func (p *planner) rebuildTable(from, to *schema.Table, ts *schema.Schema, columnSteps []*Step) {
+	d := p.d
+	p.rebuilt = true
+	tmp := *to
+	tmp.Name = fmt.Sprintf("%s__new", to.Name)
+	common := []string{}
+	for _, c := range to.Columns {
+		if _, err := from.FindColumnByName(c.Name); err == nil {
+			common = append(common, c.Name)
+		}
+	}
+	dropped := []string{}
+	for _, c := range from.Columns {
+		if _, err := to.FindColumnByName(c.Name); err != nil {
+			dropped = append(dropped, c.Name)
+		}
+	}
+	warnings := []string{fmt.Sprintf("rebuild table %s", to.Name)}
+	destructive := len(dropped) > 0
+	if destructive {
+		warnings[0] = fmt.Sprintf("%s and drop column %s", warnings[0], strings.Join(dropped, ", "))
+	}
+	for _, s := range columnSteps {
+		if s.Warning != "" {
+			warnings = append(warnings, s.Warning)
+		}
+		destructive = destructive || s.Destructive
+	}
+	cols := d.Columns(nil, common)
+	p.add(phaseAlterColumns, &Step{
+		SQL:         d.CreateTable(&tmp, foreignKeys(to, ts)),
+		Destructive: destructive,
+		Warning:     strings.Join(warnings, "; "),
+	})
+	p.add(phaseAlterColumns, &Step{SQL: fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s;", d.TableName(tmp.Name), cols, cols, d.TableName(from.Name))})
+	p.add(phaseAlterColumns, &Step{SQL: fmt.Sprintf("DROP TABLE %s;", d.TableName(from.Name))})
+	p.add(phaseAlterColumns, &Step{SQL: fmt.Sprintf("ALTER TABLE %s RENAME TO %s;", d.TableName(tmp.Name), d.TableName(to.Name))})
+	for _, i := range ddl.Indexes(to) {
+		p.add(phaseCreateIndexes, &Step{SQL: d.CreateIndex(to, i)})
+	}
+	// triggers are dropped with the table
+	for _, t := range to.Triggers {
+		p.add(phaseCreateIndexes, &Step{SQL: fmt.Sprintf("%s;", strings.TrimSuffix(strings.TrimSpace(t.Def), ";"))})
+	}
+}
+
+// tables return the tables ( not views ) of the schema
+func tables(s *schema.Schema) []*schema.Table {
+	tables := []*schema.Table{}
+	for _, t := range s.Tables {
+		if ddl.IsTable(t) {
+			tables = append(tables, t)
+		}
+	}
+	return tables
+}
+
+// foreignKeys return relations ( not virtual ) of the table as the child
+func foreignKeys(t *schema.Table, s *schema.Schema) []*schema.Relation {
+	rs := []*schema.Relation{}
+	for _, r := range s.Relations {
+		if r.Table == t && !r.Virtual {
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+// constraints return definitions of unique and check constraints by name
+func constraints(d ddl.Dialect, t *schema.Table) map[string]string {
+	defs := map[string]string{}
+	for _, c := range t.Constraints {
+		switch strings.ToUpper(c.Type) {
+		case "PRIMARY KEY", "P":
+			continue
+		}
+		if def, ok := d.ConstraintDef(c); ok {
+			defs[c.Name] = def
+		}
+	}
+	return defs
+}
+
+// indexes return CREATE INDEX statements by name
+func indexes(d ddl.Dialect, t *schema.Table) map[string]string {
+	stmts := map[string]string{}
+	for _, i := range ddl.Indexes(t) {
+		stmts[i.Name] = d.CreateIndex(t, i)
+	}
+	return stmts
+}
+
+func primaryKeyColumns(c *schema.Constraint) string {
+	if c == nil {
+		return ""
+	}
+	return strings.Join(c.Columns, ",")
+}
@@ -0,0 +1,242 @@
+package migrate
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/datasource"
+	"github.com/tmdc-io/tbls/output/ddl"
+	"github.com/tmdc-io/tbls/schema"
+)
+
+func TestNoChanges(t *testing.T) {
+	from := testSchema(t)
+	to := testSchema(t)
+	for _, d := range []ddl.Dialect{ddl.Postgres, ddl.MySQL, ddl.SQLite} {
+		p := New(from, to, d)
+		if len(p.Steps) != 0 {
+			t.Errorf("%s: got %d steps\nwant no steps", d, len(p.Steps))
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		dialect         ddl.Dialect
+		want            []string
+		wantDestructive int
+	}{
+		{
+			ddl.Postgres,
+			[]string{
+				`DROP INDEX "public"."posts_user_id_idx";`,
+				`ALTER TABLE "public"."users" ADD COLUMN "nickname" varchar(30);`,
+				`ALTER TABLE "public"."posts" ALTER COLUMN "title" TYPE text USING "title"::text;`,
+				`ALTER TABLE "public"."users" DROP COLUMN "updated";`,
+				`CREATE INDEX "users_created_idx" ON "public"."users" ("created");`,
+				`DROP TABLE "public"."logs";`,
+			},
+			3,
+		},
+		{
+			ddl.MySQL,
+			[]string{
+				"DROP INDEX `posts_user_id_idx` ON `posts`;",
+				"ALTER TABLE `users` ADD COLUMN `nickname` varchar(30);",
+				"ALTER TABLE `posts` MODIFY COLUMN `title` text NOT NULL;",
+				"ALTER TABLE `users` DROP COLUMN `updated`;",
+				"CREATE INDEX `users_created_idx` ON `users` (`created`);",
+				"DROP TABLE `logs`;",
+			},
+			3,
+		},
+	}
+	for _, tt := range tests {
+		from := testSchema(t)
+		to := testSchema(t)
+		modify(t, to)
+		p := New(from, to, tt.dialect)
+		got := []string{}
+		for _, s := range p.Steps {
+			got = append(got, s.SQL)
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s: %s", tt.dialect, diff)
+		}
+		if got := len(p.Destructive()); got != tt.wantDestructive {
+			t.Errorf("%s: got %v\nwant %v", tt.dialect, got, tt.wantDestructive)
+		}
+	}
+}
+
+func TestSQLiteRebuild(t *testing.T) {
+	from := testSchema(t)
+	to := testSchema(t)
+	modify(t, to)
+	p := New(from, to, ddl.SQLite)
+	buf := &bytes.Buffer{}
+	if err := p.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`ALTER TABLE "users" ADD COLUMN "nickname"`,
+		`-- DESTRUCTIVE: rebuild table public.posts; change type of public.posts.title from varchar(255) to text may lose data or fail
+CREATE TABLE "posts__new" (`,
+		`ALTER TABLE "posts__new" RENAME TO "posts";`,
+		`-- DESTRUCTIVE: drop table public.logs`,
+		`-- 3 destructive step(s)`,
+	} {
+		if !bytes.Contains(buf.Bytes(), []byte(want)) {
+			t.Errorf("got %v\nwant %v", buf.String(), want)
+		}
+	}
+}
+
+func TestSQLiteRebuildParent(t *testing.T) {
+	from := testSchema(t)
+	to := testSchema(t)
+	// public.posts is referenced by public.comments and has a trigger
+	posts, err := to.FindTableByName("public.posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	title, err := posts.FindColumnByName("title")
+	if err != nil {
+		t.Fatal(err)
+	}
+	title.Type = "text"
+	p := New(from, to, ddl.SQLite)
+	got := []string{}
+	for _, s := range p.Steps {
+		got = append(got, s.SQL)
+	}
+	want := []string{
+		"PRAGMA foreign_keys=OFF;",
+		"BEGIN;",
+		`INSERT INTO "posts__new" ("id", "user_id", "title", "body", "post_type", "labels", "created", "updated") SELECT "id", "user_id", "title", "body", "post_type", "labels", "created", "updated" FROM "posts";`,
+		`DROP TABLE "posts";`,
+		`ALTER TABLE "posts__new" RENAME TO "posts";`,
+		"CREATE CONSTRAINT TRIGGER update_posts_updated AFTER INSERT OR UPDATE ON public.posts NOT DEFERRABLE INITIALLY IMMEDIATE FOR EACH ROW EXECUTE PROCEDURE update_updated();",
+		"PRAGMA foreign_key_check;",
+		"COMMIT;",
+		"PRAGMA foreign_keys=ON;",
+	}
+	i := 0
+	for _, s := range got {
+		if i < len(want) && s == want[i] {
+			i++
+		}
+	}
+	if i != len(want) {
+		t.Errorf("got %v\nwant %v in order", got, want)
+	}
+	if got[len(got)-1] != "PRAGMA foreign_keys=ON;" {
+		t.Errorf("got %v\nwant %v", got[len(got)-1], "PRAGMA foreign_keys=ON;")
+	}
+	for _, s := range got {
+		if strings.Contains(s, "comments__new") {
+			t.Errorf("got %v\nwant no rebuild of the child table", s)
+		}
+	}
+}
+
+func TestDropTablesOrder(t *testing.T) {
+	tests := []struct {
+		dialect ddl.Dialect
+		want    []string
+	}{
+		{ddl.Postgres, []string{`DROP TABLE "public"."hyphen-table";`, `DROP TABLE "public"."CamelizeTable";`}},
+		{ddl.MySQL, []string{"DROP TABLE `hyphen-table`;", "DROP TABLE `CamelizeTable`;"}},
+		{ddl.SQLite, []string{`DROP TABLE "hyphen-table";`, `DROP TABLE "CamelizeTable";`}},
+	}
+	for _, tt := range tests {
+		from := testSchema(t)
+		to := testSchema(t)
+		// public.hyphen-table references public.CamelizeTable, which comes first in the schema
+		tables := []*schema.Table{}
+		for _, tbl := range to.Tables {
+			if tbl.Name != "public.CamelizeTable" && tbl.Name != "public.hyphen-table" {
+				tables = append(tables, tbl)
+			}
+		}
+		to.Tables = tables
+		relations := []*schema.Relation{}
+		for _, r := range to.Relations {
+			if r.Table.Name != "public.hyphen-table" {
+				relations = append(relations, r)
+			}
+		}
+		to.Relations = relations
+		p := New(from, to, tt.dialect)
+		got := []string{}
+		for _, s := range p.Steps {
+			got = append(got, s.SQL)
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s: %s", tt.dialect, diff)
+		}
+	}
+}
+
+func testSchema(t *testing.T) *schema.Schema {
+	t.Helper()
+	s, err := datasource.Analyze(config.DSN{URL: "json://../testdata/testdb.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// modify drop public.logs, add and drop columns of public.users, change type of public.posts.title,
+// and replace an index
+func modify(t *testing.T, s *schema.Schema) {
+	t.Helper()
+	users, err := s.FindTableByName("public.users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	users.Columns = append(users.Columns, &schema.Column{Name: "nickname", Type: "varchar(30)", Nullable: true})
+	cols := []*schema.Column{}
+	for _, c := range users.Columns {
+		if c.Name != "updated" {
+			cols = append(cols, c)
+		}
+	}
+	users.Columns = cols
+	users.Indexes = append(users.Indexes, &schema.Index{Name: "users_created_idx", Columns: []string{"created"}})
+
+	posts, err := s.FindTableByName("public.posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	title, err := posts.FindColumnByName("title")
+	if err != nil {
+		t.Fatal(err)
+	}
+	title.Type = "text"
+	indexes := []*schema.Index{}
+	for _, i := range posts.Indexes {
+		if i.Name != "posts_user_id_idx" {
+			indexes = append(indexes, i)
+		}
+	}
+	posts.Indexes = indexes
+
+	tables := []*schema.Table{}
+	for _, tbl := range s.Tables {
+		if tbl.Name != "public.logs" {
+			tables = append(tables, tbl)
+		}
+	}
+	s.Tables = tables
+	relations := []*schema.Relation{}
+	for _, r := range s.Relations {
+		if r.Table.Name != "public.logs" && r.ParentTable.Name != "public.logs" {
+			relations = append(relations, r)
+		}
+	}
+	s.Relations = relations
+}
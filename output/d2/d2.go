@@ -0,0 +1,184 @@
+package d2
+
+import (
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/gobuffalo/packr/v2"
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/output"
+	"github.com/tmdc-io/tbls/schema"
+	"github.com/pkg/errors"
+)
+
+// D2 struct
+type D2 struct {
+	config *config.Config
+	box    *packr.Box
+}
+
+// Table is a table with the columns for D2 templates
+type Table struct {
+	*schema.Table
+	Columns []*Column
+}
+
+// Column is a column with the constraints of sql_table shape
+type Column struct {
+	*schema.Column
+	Constraints []string
+}
+
+// New return D2
+func New(c *config.Config) *D2 {
+	return &D2{
+		config: c,
+		box:    packr.New("d2", "./templates"),
+	}
+}
+
+func (d *D2) schemaTemplate() (string, error) {
+	if len(d.config.Templates.D2.Schema) > 0 {
+		tb, err := os.ReadFile(d.config.Templates.D2.Schema)
+		if err != nil {
+			return string(tb), errors.WithStack(err)
+		}
+		return string(tb), nil
+	} else {
+		ts, err := d.box.FindString("schema.d2.tmpl")
+		if err != nil {
+			return ts, errors.WithStack(err)
+		}
+		return ts, nil
+	}
+}
+
+func (d *D2) tableTemplate() (string, error) {
+	if len(d.config.Templates.D2.Table) > 0 {
+		tb, err := os.ReadFile(d.config.Templates.D2.Table)
+		if err != nil {
+			return string(tb), errors.WithStack(err)
+		}
+		return string(tb), nil
+	} else {
+		ts, err := d.box.FindString("table.d2.tmpl")
+		if err != nil {
+			return ts, errors.WithStack(err)
+		}
+		return ts, nil
+	}
+}
+
+// OutputSchema output D2 format for full relation.
+func (d *D2) OutputSchema(wr io.Writer, s *schema.Schema) error {
+	ts, err := d.schemaTemplate()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	tmpl := template.Must(template.New(s.Name).Funcs(output.Funcs(&d.config.MergedDict)).Parse(ts))
+	err = tmpl.Execute(wr, map[string]interface{}{
+		"Schema":      s,
+		"Tables":      newTables(s.Tables),
+		"Relations":   output.NewRelations(s.Relations),
+		"showComment": d.config.ER.Comment,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// OutputTable output D2 format for table.
+func (d *D2) OutputTable(wr io.Writer, t *schema.Table) error {
+	direction, err := schema.ParseDirection(d.config.ER.Direction)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	tables, relations := t.CollectRelatedTables(*d.config.ER.Distance, direction)
+	ts, err := d.tableTemplate()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	tmpl := template.Must(template.New(t.Name).Funcs(output.Funcs(&d.config.MergedDict)).Parse(ts))
+	err = tmpl.Execute(wr, map[string]interface{}{
+		"Table":       newTable(tables[0]),
+		"Tables":      newTables(tables[1:]),
+		"Relations":   output.NewRelations(relations),
+		"showComment": d.config.ER.Comment,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+func newTables(tables []*schema.Table) []*Table {
+	ts := []*Table{}
+	for _, t := range tables {
+		ts = append(ts, newTable(t))
+	}
+	return ts
+}
+
+func newTable(t *schema.Table) *Table {
+	pk := map[string]struct{}{}
+	unique := map[string]struct{}{}
+	for _, c := range t.Constraints {
+		switch {
+		case c.Type == "PRIMARY KEY" || c.Type == "p":
+			for _, cc := range c.Columns {
+				pk[cc] = struct{}{}
+			}
+		case (c.Type == "UNIQUE" || c.Type == "u") && len(c.Columns) == 1:
+			unique[c.Columns[0]] = struct{}{}
+		}
+	}
+	for _, i := range t.Indexes {
+		if strings.Contains(i.Def, "PRIMARY") {
+			for _, cc := range i.Columns {
+				pk[cc] = struct{}{}
+			}
+		}
+	}
+
+	columns := []*Column{}
+	for _, c := range t.Columns {
+		constraints := []string{}
+		if _, ok := pk[c.Name]; ok {
+			constraints = append(constraints, "primary_key")
+		}
+		for _, r := range c.ParentRelations {
+			if !r.Virtual {
+				constraints = append(constraints, "foreign_key")
+				break
+			}
+		}
+		if _, ok := unique[c.Name]; ok {
+			constraints = append(constraints, "unique")
+		}
+		columns = append(columns, &Column{
+			Column:      c,
+			Constraints: constraints,
+		})
+	}
+	return &Table{
+		Table:   t,
+		Columns: columns,
+	}
+}
+
+// Constraint return the value of constraint keyword of sql_table shape
+func (c *Column) Constraint() string {
+	switch len(c.Constraints) {
+	case 0:
+		return ""
+	case 1:
+		return c.Constraints[0]
+	default:
+		return "[" + strings.Join(c.Constraints, "; ") + "]"
+	}
+}
@@ -0,0 +1,164 @@
+package d2
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/schema"
+)
+
+func TestOutputSchema(t *testing.T) {
+	tests := []struct {
+		comment bool
+		want    string
+	}{
+		{false, "d2_test_schema.d2.golden"},
+		{true, "d2_test_schema_comment.d2.golden"},
+	}
+	for _, tt := range tests {
+		s := newTestSchema()
+		c, err := config.New()
+		if err != nil {
+			t.Error(err)
+		}
+		err = c.LoadConfigFile(filepath.Join(testdataDir(), "out_test_tbls.yml"))
+		if err != nil {
+			t.Error(err)
+		}
+		err = c.MergeAdditionalData(s)
+		if err != nil {
+			t.Error(err)
+		}
+		c.ER.Comment = tt.comment
+		o := New(c)
+		buf := &bytes.Buffer{}
+		err = o.OutputSchema(buf, s)
+		if err != nil {
+			t.Error(err)
+		}
+		want, _ := os.ReadFile(filepath.Join(testdataDir(), tt.want))
+		got := buf.String()
+		if got != string(want) {
+			t.Errorf("got %v\nwant %v", got, string(want))
+		}
+	}
+}
+
+func TestOutputTable(t *testing.T) {
+	s := newTestSchema()
+	c, err := config.New()
+	if err != nil {
+		t.Error(err)
+	}
+	err = c.LoadConfigFile(filepath.Join(testdataDir(), "out_test_tbls.yml"))
+	if err != nil {
+		t.Error(err)
+	}
+	err = c.MergeAdditionalData(s)
+	if err != nil {
+		t.Error(err)
+	}
+	s.Relations[0].Virtual = true
+	ta := s.Tables[0]
+
+	o := New(c)
+	buf := &bytes.Buffer{}
+	_ = o.OutputTable(buf, ta)
+	want, _ := os.ReadFile(filepath.Join(testdataDir(), "d2_test_a.d2.golden"))
+	got := buf.String()
+	if got != string(want) {
+		t.Errorf("got %v\nwant %v", got, string(want))
+	}
+}
+
+func testdataDir() string {
+	wd, _ := os.Getwd()
+	dir, _ := filepath.Abs(filepath.Join(filepath.Dir(filepath.Dir(wd)), "testdata"))
+	return dir
+}
+func newTestSchema() *schema.Schema {
+	ca := &schema.Column{
+		Name:    "a",
+		Type:    "int",
+		Comment: "column a",
+	}
+	cb := &schema.Column{
+		Name:    "b",
+		Type:    "int",
+		Comment: "column b",
+	}
+
+	ta := &schema.Table{
+		Name:    "a",
+		Comment: "table a",
+		Columns: []*schema.Column{
+			ca,
+			&schema.Column{
+				Name:    "a2",
+				Comment: "column a2",
+			},
+		},
+	}
+	ta.Indexes = []*schema.Index{
+		&schema.Index{
+			Name:    "PRIMARY KEY",
+			Def:     "PRIMARY KEY(a)",
+			Table:   &ta.Name,
+			Columns: []string{"a"},
+		},
+	}
+	ta.Constraints = []*schema.Constraint{
+		&schema.Constraint{
+			Name:    "PRIMARY",
+			Type:    "PRIMARY KEY",
+			Table:   &ta.Name,
+			Def:     "PRIMARY KEY (a)",
+			Columns: []string{"a"},
+		},
+	}
+	ta.Triggers = []*schema.Trigger{
+		&schema.Trigger{
+			Name: "update_a_a2",
+			Def:  "CREATE CONSTRAINT TRIGGER update_a_a2 AFTER INSERT OR UPDATE ON a",
+		},
+	}
+	tb := &schema.Table{
+		Name:    "b",
+		Comment: "table b",
+		Columns: []*schema.Column{
+			cb,
+			&schema.Column{
+				Name:    "b2",
+				Comment: "column b2",
+			},
+		},
+	}
+	r := &schema.Relation{
+		Table:         tb,
+		Columns:       []*schema.Column{cb},
+		ParentTable:   ta,
+		ParentColumns: []*schema.Column{ca},
+		Def:           "FOREIGN KEY (b) REFERENCES a(a)",
+	}
+	ca.ChildRelations = []*schema.Relation{r}
+	cb.ParentRelations = []*schema.Relation{r}
+
+	s := &schema.Schema{
+		Name: "testschema",
+		Tables: []*schema.Table{
+			ta,
+			tb,
+		},
+		Relations: []*schema.Relation{
+			r,
+		},
+		Driver: &schema.Driver{
+			Name:            "testdriver",
+			DatabaseVersion: "1.0.0",
+		},
+	}
+	return s
+}
@@ -0,0 +1,316 @@
+package dbml
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/output"
+	"github.com/tmdc-io/tbls/schema"
+	"github.com/pkg/errors"
+)
+
+var (
+	simpleTypeRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\([0-9,]*\))?(\[\])?$`)
+	enumTypeRe   = regexp.MustCompile(`(?i)^enum\((.*)\)$`)
+	enumValueRe  = regexp.MustCompile(`'((?:[^']|'')*)'`)
+	numberRe     = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+	stringRe     = regexp.MustCompile(`^'((?:[^']|'')*)'$`)
+	usingRe      = regexp.MustCompile(`(?i) USING (btree|hash)\b`)
+)
+
+// DBML struct
+type DBML struct {
+	config *config.Config
+}
+
+// New return DBML
+func New(c *config.Config) *DBML {
+	return &DBML{
+		config: c,
+	}
+}
+
+// OutputSchema output DBML format for full relation.
+func (d *DBML) OutputSchema(wr io.Writer, s *schema.Schema) error {
+	return d.output(wr, s, s.Tables, s.Relations)
+}
+
+// OutputTable output DBML format for table and the tables related to it.
+func (d *DBML) OutputTable(wr io.Writer, t *schema.Table) error {
+	direction, err := schema.ParseDirection(d.config.ER.Direction)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	tables, relations := t.CollectRelatedTables(*d.config.ER.Distance, direction)
+	return d.output(wr, &schema.Schema{Name: t.Name}, tables, relations)
+}
+
+func (d *DBML) output(wr io.Writer, s *schema.Schema, tables []*schema.Table, relations []*schema.Relation) error {
+	b := &strings.Builder{}
+
+	// project
+	fmt.Fprintf(b, "Project %s {\n", quoteName(s.Name))
+	if s.Driver != nil {
+		databaseType := s.Driver.Name
+		if databaseType == "dbml" {
+			databaseType = s.Driver.DatabaseVersion
+		}
+		if databaseType != "" {
+			fmt.Fprintf(b, "  database_type: %s\n", quoteString(databaseType))
+		}
+	}
+	if s.Desc != "" {
+		fmt.Fprintf(b, "  Note: %s\n", quoteString(s.Desc))
+	}
+	b.WriteString("}\n")
+
+	// tables
+	enums := []string{}
+	for _, t := range tables {
+		enums = append(enums, writeTable(b, t)...)
+	}
+
+	// enums
+	for _, e := range enums {
+		b.WriteString("\n")
+		b.WriteString(e)
+	}
+
+	// relations
+	rs := output.NewRelations(relations)
+	for _, r := range rs {
+		if r.Virtual {
+			continue
+		}
+		b.WriteString("\n")
+		writeRef(b, r)
+	}
+
+	// table groups
+	groups := []string{}
+	grouped := map[string][]*schema.Table{}
+	for _, t := range tables {
+		for _, l := range t.Labels {
+			if _, ok := grouped[l.Name]; !ok {
+				groups = append(groups, l.Name)
+			}
+			grouped[l.Name] = append(grouped[l.Name], t)
+		}
+	}
+	for _, g := range groups {
+		fmt.Fprintf(b, "\nTableGroup %s {\n", quoteName(g))
+		for _, t := range grouped[g] {
+			fmt.Fprintf(b, "  %s\n", tableName(t.Name))
+		}
+		b.WriteString("}\n")
+	}
+
+	if _, err := io.WriteString(wr, b.String()); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// writeTable write the Table block and return Enum blocks of the enum columns
+func writeTable(b *strings.Builder, t *schema.Table) []string {
+	pk := primaryKeyColumns(t)
+	enums := []string{}
+
+	fmt.Fprintf(b, "\nTable %s {\n", tableName(t.Name))
+	for _, c := range t.Columns {
+		typ := c.Type
+		if m := enumTypeRe.FindStringSubmatch(c.Type); m != nil {
+			name := fmt.Sprintf("%s_%s_enum", t.Name, c.Name)
+			e := &strings.Builder{}
+			fmt.Fprintf(e, "Enum %s {\n", tableName(name))
+			for _, v := range enumValueRe.FindAllStringSubmatch(m[1], -1) {
+				fmt.Fprintf(e, "  %s\n", quoteName(strings.ReplaceAll(v[1], "''", "'")))
+			}
+			e.WriteString("}\n")
+			enums = append(enums, e.String())
+			typ = tableName(name)
+		} else if !simpleTypeRe.MatchString(typ) {
+			typ = quoteName(typ)
+		}
+
+		settings := []string{}
+		if len(pk) == 1 && pk[0] == c.Name {
+			settings = append(settings, "pk")
+		} else if !c.Nullable {
+			settings = append(settings, "not null")
+		}
+		if c.Default.Valid {
+			if strings.HasPrefix(c.Default.String, "nextval(") {
+				settings = append(settings, "increment")
+			} else {
+				settings = append(settings, fmt.Sprintf("default: %s", defaultValue(c.Default.String)))
+			}
+		}
+		if strings.Contains(strings.ToLower(c.ExtraDef), "auto_increment") {
+			settings = append(settings, "increment")
+		}
+		if c.Comment != "" {
+			settings = append(settings, fmt.Sprintf("note: %s", quoteString(c.Comment)))
+		}
+		fmt.Fprintf(b, "  %s %s", quoteName(c.Name), typ)
+		if len(settings) > 0 {
+			fmt.Fprintf(b, " [%s]", strings.Join(settings, ", "))
+		}
+		b.WriteString("\n")
+	}
+
+	indexes := []string{}
+	if len(pk) > 1 {
+		indexes = append(indexes, fmt.Sprintf("    %s [pk]\n", indexColumns(t, pk)))
+	}
+	for _, i := range t.Indexes {
+		if strings.Contains(i.Def, "PRIMARY") || len(i.Columns) == 0 {
+			continue
+		}
+		settings := []string{}
+		if strings.Contains(strings.ToUpper(i.Def), "UNIQUE") {
+			settings = append(settings, "unique")
+		}
+		settings = append(settings, fmt.Sprintf("name: %s", quoteString(i.Name)))
+		if m := usingRe.FindStringSubmatch(i.Def); m != nil {
+			settings = append(settings, fmt.Sprintf("type: %s", strings.ToLower(m[1])))
+		}
+		if i.Comment != "" {
+			settings = append(settings, fmt.Sprintf("note: %s", quoteString(i.Comment)))
+		}
+		indexes = append(indexes, fmt.Sprintf("    %s [%s]\n", indexColumns(t, i.Columns), strings.Join(settings, ", ")))
+	}
+	if len(indexes) > 0 {
+		b.WriteString("\n  indexes {\n")
+		for _, i := range indexes {
+			b.WriteString(i)
+		}
+		b.WriteString("  }\n")
+	}
+
+	if t.Comment != "" {
+		fmt.Fprintf(b, "\n  Note: %s\n", quoteString(t.Comment))
+	}
+	b.WriteString("}\n")
+
+	return enums
+}
+
+func writeRef(b *strings.Builder, r *output.Relation) {
+	name := ""
+	for _, c := range r.Table.Constraints {
+		if c.Def == r.Def && c.Name != "" {
+			name = fmt.Sprintf(" %s", quoteName(c.Name))
+			break
+		}
+	}
+	op := ">"
+	switch {
+	case r.ParentCardinality == schema.ZeroOrMore || r.ParentCardinality == schema.OneOrMore:
+		op = "<>"
+	case r.Cardinality == schema.ZeroOrOne || r.Cardinality == schema.ExactlyOne:
+		op = "-"
+	}
+	settings := []string{}
+	upper := strings.ToUpper(r.Def)
+	for _, a := range []string{"DELETE", "UPDATE"} {
+		i := strings.Index(upper, fmt.Sprintf("ON %s ", a))
+		if i < 0 {
+			continue
+		}
+		action := upper[i+len(a)+4:]
+		for _, v := range []string{"CASCADE", "RESTRICT", "SET NULL", "SET DEFAULT", "NO ACTION"} {
+			if strings.HasPrefix(action, v) {
+				settings = append(settings, fmt.Sprintf("%s: %s", strings.ToLower(a), strings.ToLower(v)))
+				break
+			}
+		}
+	}
+	fmt.Fprintf(b, "Ref%s: %s %s %s", name, refColumns(r.Table, r.Columns), op, refColumns(r.ParentTable, r.ParentColumns))
+	if len(settings) > 0 {
+		fmt.Fprintf(b, " [%s]", strings.Join(settings, ", "))
+	}
+	b.WriteString("\n")
+}
+
+// indexColumns return the columns of the index. Columns not in the table are written as expressions.
+func indexColumns(t *schema.Table, columns []string) string {
+	cols := []string{}
+	for _, c := range columns {
+		if _, err := t.FindColumnByName(c); err != nil {
+			cols = append(cols, fmt.Sprintf("`%s`", strings.ReplaceAll(c, "`", "'")))
+			continue
+		}
+		cols = append(cols, quoteName(c))
+	}
+	if len(cols) == 1 {
+		return cols[0]
+	}
+	return fmt.Sprintf("(%s)", strings.Join(cols, ", "))
+}
+
+func refColumns(t *schema.Table, columns []*schema.Column) string {
+	if len(columns) == 1 {
+		return fmt.Sprintf("%s.%s", tableName(t.Name), quoteName(columns[0].Name))
+	}
+	cols := []string{}
+	for _, c := range columns {
+		cols = append(cols, quoteName(c.Name))
+	}
+	return fmt.Sprintf("%s.(%s)", tableName(t.Name), strings.Join(cols, ", "))
+}
+
+func primaryKeyColumns(t *schema.Table) []string {
+	for _, c := range t.Constraints {
+		switch strings.ToUpper(c.Type) {
+		case "PRIMARY KEY", "P":
+			if len(c.Columns) > 0 {
+				return c.Columns
+			}
+		}
+	}
+	for _, i := range t.Indexes {
+		if strings.Contains(i.Def, "PRIMARY") {
+			return i.Columns
+		}
+	}
+	return nil
+}
+
+// tableName return the quoted table name ( "schema"."table" if the name has a schema part )
+func tableName(name string) string {
+	if i := strings.Index(name, "."); i > 0 {
+		return fmt.Sprintf("%s.%s", quoteName(name[:i]), quoteName(name[i+1:]))
+	}
+	return quoteName(name)
+}
+
+func quoteName(name string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return fmt.Sprintf(`"%s"`, r.Replace(name))
+}
+
+func quoteString(s string) string {
+	if strings.ContainsAny(s, "\r\n") {
+		r := strings.NewReplacer(`\`, `\\`, `'''`, `\'''`, "\r\n", "\n")
+		return fmt.Sprintf("'''%s'''", r.Replace(s))
+	}
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	return fmt.Sprintf("'%s'", r.Replace(s))
+}
+
+func defaultValue(v string) string {
+	switch {
+	case numberRe.MatchString(v):
+		return v
+	case strings.EqualFold(v, "true") || strings.EqualFold(v, "false") || strings.EqualFold(v, "null"):
+		return strings.ToLower(v)
+	}
+	if m := stringRe.FindStringSubmatch(v); m != nil {
+		return quoteString(strings.ReplaceAll(m[1], "''", "'"))
+	}
+	return fmt.Sprintf("`%s`", strings.ReplaceAll(v, "`", "'"))
+}
@@ -0,0 +1,119 @@
+package ddl
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/schema"
+	"github.com/pkg/errors"
+)
+
+// DDL struct
+type DDL struct {
+	config  *config.Config
+	dialect Dialect
+}
+
+// New return DDL
+func New(c *config.Config, d Dialect) *DDL {
+	return &DDL{
+		config:  c,
+		dialect: d,
+	}
+}
+
+// OutputSchema output DDL of all tables.
+// Tables are created first and foreign keys are added after all tables are created ( inline for SQLite ).
+func (d *DDL) OutputSchema(wr io.Writer, s *schema.Schema) error {
+	stmts := []string{}
+	for _, t := range s.Tables {
+		stmts = append(stmts, d.table(t, s.Relations)...)
+	}
+	if d.dialect != SQLite {
+		fks := []string{}
+		for _, r := range s.Relations {
+			if r.Virtual || !IsTable(r.Table) {
+				continue
+			}
+			fks = append(fks, fmt.Sprintf("ALTER TABLE %s ADD %s;", d.dialect.TableName(r.Table.Name), d.dialect.ForeignKeyDef(r)))
+		}
+		if len(fks) > 0 {
+			stmts = append(stmts, strings.Join(fks, "\n"))
+		}
+	}
+	return d.write(wr, stmts)
+}
+
+// OutputTable output DDL of the table.
+func (d *DDL) OutputTable(wr io.Writer, t *schema.Table) error {
+	relations := []*schema.Relation{}
+	for _, c := range t.Columns {
+		relations = append(relations, c.ParentRelations...)
+	}
+	stmts := d.table(t, relations)
+	if d.dialect != SQLite {
+		fks := []string{}
+		for _, r := range uniqueRelations(relations) {
+			if r.Virtual || !IsTable(t) {
+				continue
+			}
+			fks = append(fks, fmt.Sprintf("ALTER TABLE %s ADD %s;", d.dialect.TableName(t.Name), d.dialect.ForeignKeyDef(r)))
+		}
+		if len(fks) > 0 {
+			stmts = append(stmts, strings.Join(fks, "\n"))
+		}
+	}
+	return d.write(wr, stmts)
+}
+
+// table return CREATE TABLE, CREATE INDEX and COMMENT statements of the table
+func (d *DDL) table(t *schema.Table, relations []*schema.Relation) []string {
+	if !IsTable(t) {
+		return []string{fmt.Sprintf("-- %s %s is skipped", strings.ToLower(t.Type), t.Name)}
+	}
+	inline := []*schema.Relation{}
+	if d.dialect == SQLite {
+		for _, r := range uniqueRelations(relations) {
+			if r.Table == t && !r.Virtual {
+				inline = append(inline, r)
+			}
+		}
+	}
+	stmts := []string{d.dialect.CreateTable(t, inline)}
+	indexes := []string{}
+	for _, i := range Indexes(t) {
+		indexes = append(indexes, d.dialect.CreateIndex(t, i))
+	}
+	indexes = append(indexes, d.dialect.Comments(t)...)
+	if len(indexes) > 0 {
+		stmts = append(stmts, strings.Join(indexes, "\n"))
+	}
+	return stmts
+}
+
+func (d *DDL) write(wr io.Writer, stmts []string) error {
+	if _, err := fmt.Fprintf(wr, "-- Generated by tbls ( dialect: %s )\n\n%s\n", d.dialect, strings.Join(stmts, "\n\n")); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// IsTable return true if the table is a table ( not a view )
+func IsTable(t *schema.Table) bool {
+	return !strings.Contains(strings.ToUpper(t.Type), "VIEW")
+}
+
+func uniqueRelations(relations []*schema.Relation) []*schema.Relation {
+	seen := map[*schema.Relation]struct{}{}
+	rs := []*schema.Relation{}
+	for _, r := range relations {
+		if _, ok := seen[r]; ok {
+			continue
+		}
+		seen[r] = struct{}{}
+		rs = append(rs, r)
+	}
+	return rs
+}
@@ -0,0 +1,81 @@
+package ddl
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/datasource"
+)
+
+func TestOutputSchema(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		want    string
+	}{
+		{Postgres, "ddl_test_postgres.sql.golden"},
+		{MySQL, "ddl_test_mysql.sql.golden"},
+		{SQLite, "ddl_test_sqlite.sql.golden"},
+	}
+	for _, tt := range tests {
+		s, err := datasource.Analyze(config.DSN{URL: "json://../../testdata/testdb.json"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := config.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		o := New(c, tt.dialect)
+		buf := &bytes.Buffer{}
+		if err := o.OutputSchema(buf, s); err != nil {
+			t.Fatal(err)
+		}
+		want, _ := os.ReadFile(filepath.Join(testdataDir(), tt.want))
+		got := buf.String()
+		if got != string(want) {
+			t.Errorf("got %v\nwant %v", got, string(want))
+		}
+	}
+}
+
+func TestConvertType(t *testing.T) {
+	tests := []struct {
+		in       string
+		postgres string
+		mysql    string
+		sqlite   string
+	}{
+		{"character varying(255)", "varchar(255)", "varchar(255)", "varchar(255)"},
+		{"varchar", "varchar", "varchar(255)", "varchar"},
+		{"int(11)", "integer", "int", "integer"},
+		{"int(10) unsigned", "integer", "int unsigned", "integer"},
+		{"tinyint(1)", "boolean", "tinyint(1)", "boolean"},
+		{"timestamp without time zone", "timestamp", "datetime", "timestamp"},
+		{"timestamp with time zone", "timestamptz", "timestamp", "timestamp"},
+		{"datetime(6)", "timestamp(6)", "datetime(6)", "timestamp"},
+		{"numeric(10, 2)", "numeric(10,2)", "decimal(10,2)", "numeric(10,2)"},
+		{"jsonb", "jsonb", "json", "text"},
+		{"bytea", "bytea", "longblob", "blob"},
+		{"uuid", "uuid", "char(36)", "text"},
+		{"integer[]", "integer[]", "json", "text"},
+		{"ARRAY", "text[]", "json", "text"},
+		{"enum('a','b')", "text", "enum('a','b')", "text"},
+		{"USER-DEFINED", "USER-DEFINED", "USER-DEFINED", "USER-DEFINED"},
+	}
+	for _, tt := range tests {
+		for d, want := range map[Dialect]string{Postgres: tt.postgres, MySQL: tt.mysql, SQLite: tt.sqlite} {
+			if got := d.ConvertType(tt.in); got != want {
+				t.Errorf("%s %s: got %v\nwant %v", d, tt.in, got, want)
+			}
+		}
+	}
+}
+
+func testdataDir() string {
+	wd, _ := os.Getwd()
+	dir, _ := filepath.Abs(filepath.Join(filepath.Dir(filepath.Dir(wd)), "testdata"))
+	return dir
+}
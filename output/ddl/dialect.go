@@ -0,0 +1,552 @@
+package ddl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tmdc-io/tbls/schema"
+	"github.com/pkg/errors"
+)
+
+// Dialect is the SQL dialect of DDL
+type Dialect string
+
+const (
+	Postgres Dialect = "postgres"
+	MySQL    Dialect = "mysql"
+	SQLite   Dialect = "sqlite"
+)
+
+var (
+	typeRe       = regexp.MustCompile(`^([a-z][a-z0-9_ ]*?)\s*(?:\(([^)]*)\))?\s*(unsigned)?\s*(zerofill)?\s*((?:\[\])*)$`)
+	actionRe     = regexp.MustCompile(`(?i)\bON (DELETE|UPDATE) (CASCADE|RESTRICT|SET NULL|SET DEFAULT|NO ACTION)`)
+	castRe       = regexp.MustCompile(`^('(?:[^']|'')*')::[a-z ]+(\[\])?$`)
+	numberRe     = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+	keywordRe    = regexp.MustCompile(`^(NULL|TRUE|FALSE|true|false|CURRENT_TIMESTAMP|CURRENT_DATE|CURRENT_TIME|LOCALTIMESTAMP)(\([0-9]*\))?$`)
+	nonWordRe    = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+	integerTypes = map[string]struct{}{"integer": {}, "bigint": {}, "smallint": {}, "tinyint": {}}
+)
+
+// ParseDialect parse dialect. Driver names ( e.g. postgresql, mariadb, sqlite3 ) are also accepted.
+func ParseDialect(v string) (Dialect, error) {
+	switch strings.ToLower(v) {
+	case "postgres", "postgresql", "pg", "redshift":
+		return Postgres, nil
+	case "mysql", "mariadb", "maria":
+		return MySQL, nil
+	case "sqlite", "sqlite3":
+		return SQLite, nil
+	}
+	return "", errors.Errorf("unsupported dialect '%s'", v)
+}
+
+// DetectDialect return the dialect of the database of the schema. If it is unknown, return Postgres.
+func DetectDialect(s *schema.Schema) Dialect {
+	if s.Driver == nil {
+		return Postgres
+	}
+	d, err := ParseDialect(s.Driver.Name)
+	if err != nil {
+		return Postgres
+	}
+	return d
+}
+
+// Quote return the quoted identifier
+func (d Dialect) Quote(name string) string {
+	if d == MySQL {
+		return fmt.Sprintf("`%s`", strings.ReplaceAll(name, "`", "``"))
+	}
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(name, `"`, `""`))
+}
+
+// QuoteString return the quoted string literal
+func (d Dialect) QuoteString(s string) string {
+	s = strings.ReplaceAll(s, "'", "''")
+	if d == MySQL {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return fmt.Sprintf("'%s'", s)
+}
+
+// TableName return the quoted table name.
+// The schema part of the table name is kept only for Postgres.
+func (d Dialect) TableName(name string) string {
+	i := strings.Index(name, ".")
+	if i < 0 {
+		return d.Quote(name)
+	}
+	if d == Postgres {
+		return fmt.Sprintf("%s.%s", d.Quote(name[:i]), d.Quote(name[i+1:]))
+	}
+	return d.Quote(name[i+1:])
+}
+
+// IndexName return the quoted index name.
+// Index names of Postgres belong to the schema of the table.
+func (d Dialect) IndexName(t *schema.Table, name string) string {
+	if i := strings.Index(t.Name, "."); i > 0 && d == Postgres && !strings.Contains(name, ".") {
+		return fmt.Sprintf("%s.%s", d.Quote(t.Name[:i]), d.Quote(name))
+	}
+	return d.Quote(name)
+}
+
+// Columns return the quoted column list. Names that are not columns of the table are written as expressions.
+func (d Dialect) Columns(t *schema.Table, names []string) string {
+	cols := []string{}
+	for _, n := range names {
+		if t != nil {
+			if _, err := t.FindColumnByName(n); err != nil {
+				cols = append(cols, fmt.Sprintf("(%s)", n))
+				continue
+			}
+		}
+		cols = append(cols, d.Quote(n))
+	}
+	return strings.Join(cols, ", ")
+}
+
+// ConvertType convert the column type to the type of the dialect. Unknown types are returned as is.
+func (d Dialect) ConvertType(typ string) string {
+	lower := strings.ToLower(strings.TrimSpace(typ))
+	if strings.HasPrefix(lower, "enum(") {
+		if d == MySQL {
+			return typ
+		}
+		return "text"
+	}
+	array := false
+	if strings.HasPrefix(lower, "array") || strings.HasPrefix(lower, "_") {
+		array = true
+	}
+	m := typeRe.FindStringSubmatch(lower)
+	if m == nil {
+		return typ
+	}
+	base, args, unsigned, suffix := m[1], m[2], m[3] != "", m[5]
+	if suffix != "" {
+		array = true
+	}
+	if array {
+		switch d {
+		case MySQL:
+			return "json"
+		case SQLite:
+			return "text"
+		}
+		if lower == "array" {
+			// the element type is unknown
+			return "text[]"
+		}
+		return typ
+	}
+	canonical := canonicalType(base, args)
+	if canonical == "" {
+		return typ
+	}
+	switch d {
+	case Postgres:
+		return postgresType(canonical, base, args)
+	case MySQL:
+		t := mysqlType(canonical, args)
+		if unsigned {
+			if _, ok := integerTypes[canonical]; ok {
+				t = t + " unsigned"
+			}
+		}
+		return t
+	case SQLite:
+		return sqliteType(canonical, args)
+	}
+	return typ
+}
+
+func canonicalType(base, args string) string {
+	switch base {
+	case "character varying", "varchar", "nvarchar", "varchar2":
+		return "varchar"
+	case "character", "char", "bpchar", "nchar":
+		return "char"
+	case "integer", "int", "int4", "mediumint", "serial", "serial4":
+		return "integer"
+	case "bigint", "int8", "bigserial", "serial8":
+		return "bigint"
+	case "smallint", "int2", "smallserial":
+		return "smallint"
+	case "tinyint":
+		if args == "1" {
+			return "boolean"
+		}
+		return "tinyint"
+	case "boolean", "bool", "bit":
+		return "boolean"
+	case "text", "tinytext", "mediumtext", "longtext", "clob", "citext":
+		return "text"
+	case "timestamp", "timestamp without time zone", "datetime":
+		return "timestamp"
+	case "timestamp with time zone", "timestamptz":
+		return "timestamptz"
+	case "time", "time without time zone":
+		return "time"
+	case "date":
+		return "date"
+	case "double precision", "double", "float8":
+		return "double"
+	case "real", "float4", "float":
+		return "real"
+	case "numeric", "decimal":
+		return "decimal"
+	case "json", "jsonb":
+		return "json"
+	case "bytea", "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary":
+		return "blob"
+	case "uuid":
+		return "uuid"
+	}
+	return ""
+}
+
+func postgresType(canonical, base, args string) string {
+	switch canonical {
+	case "varchar", "char":
+		return withArgs(canonical, args)
+	case "tinyint":
+		return "smallint"
+	case "timestamp", "timestamptz", "time":
+		return withArgs(canonical, args)
+	case "double":
+		return "double precision"
+	case "decimal":
+		return withArgs("numeric", args)
+	case "json":
+		if base == "jsonb" {
+			return "jsonb"
+		}
+		return "json"
+	case "blob":
+		return "bytea"
+	}
+	return canonical
+}
+
+func mysqlType(canonical, args string) string {
+	switch canonical {
+	case "varchar":
+		if args == "" {
+			return "varchar(255)"
+		}
+		return withArgs("varchar", args)
+	case "char":
+		return withArgs("char", args)
+	case "integer":
+		return "int"
+	case "boolean":
+		return "tinyint(1)"
+	case "timestamp":
+		return withArgs("datetime", args)
+	case "timestamptz":
+		return withArgs("timestamp", args)
+	case "time":
+		return withArgs("time", args)
+	case "real":
+		return "float"
+	case "decimal":
+		return withArgs("decimal", args)
+	case "blob":
+		return "longblob"
+	case "uuid":
+		return "char(36)"
+	}
+	return canonical
+}
+
+func sqliteType(canonical, args string) string {
+	switch canonical {
+	case "integer", "bigint", "smallint", "tinyint":
+		return "integer"
+	case "varchar", "char":
+		return withArgs(canonical, args)
+	case "timestamp", "timestamptz":
+		return "timestamp"
+	case "double":
+		return "real"
+	case "decimal":
+		return withArgs("numeric", args)
+	case "json", "uuid":
+		return "text"
+	}
+	return canonical
+}
+
+func withArgs(typ, args string) string {
+	if args == "" {
+		return typ
+	}
+	return fmt.Sprintf("%s(%s)", typ, strings.ReplaceAll(args, " ", ""))
+}
+
+// AutoIncrement return true if the column is auto increment
+func AutoIncrement(c *schema.Column) bool {
+	if c.Default.Valid && strings.HasPrefix(c.Default.String, "nextval(") {
+		return true
+	}
+	lower := strings.ToLower(c.ExtraDef)
+	return strings.Contains(lower, "auto_increment") || strings.Contains(lower, "identity")
+}
+
+// DefaultValue return the default value expression of the column for the dialect.
+// String defaults without quotes ( as MySQL reports ) are quoted, and Postgres type casts of literals are removed.
+func (d Dialect) DefaultValue(c *schema.Column) (string, bool) {
+	if !c.Default.Valid || AutoIncrement(c) {
+		return "", false
+	}
+	v := strings.TrimSpace(c.Default.String)
+	if m := castRe.FindStringSubmatch(v); m != nil {
+		v = m[1]
+	}
+	switch {
+	case v == "":
+		return d.QuoteString(v), true
+	case numberRe.MatchString(v), strings.HasPrefix(v, "'"):
+		return v, true
+	case keywordRe.MatchString(v):
+		return v, true
+	case strings.EqualFold(v, "now()"):
+		return "CURRENT_TIMESTAMP", true
+	case strings.Contains(v, "("):
+		if d == MySQL || d == SQLite {
+			return fmt.Sprintf("(%s)", v), true
+		}
+		return v, true
+	}
+	return d.QuoteString(v), true
+}
+
+// ColumnDef return the column definition
+func (d Dialect) ColumnDef(c *schema.Column) string {
+	typ := d.ConvertType(c.Type)
+	def := []string{d.Quote(c.Name), typ}
+	if AutoIncrement(c) && d == Postgres {
+		def = append(def, "GENERATED BY DEFAULT AS IDENTITY")
+	}
+	if !c.Nullable {
+		def = append(def, "NOT NULL")
+	}
+	if v, ok := d.DefaultValue(c); ok {
+		def = append(def, "DEFAULT", v)
+	}
+	if AutoIncrement(c) && d == MySQL {
+		def = append(def, "AUTO_INCREMENT")
+	}
+	if c.Comment != "" && d == MySQL {
+		def = append(def, "COMMENT", d.QuoteString(c.Comment))
+	}
+	return strings.Join(def, " ")
+}
+
+// PrimaryKey return the primary key constraint of the table
+func PrimaryKey(t *schema.Table) *schema.Constraint {
+	for _, c := range t.Constraints {
+		switch strings.ToUpper(c.Type) {
+		case "PRIMARY KEY", "P":
+			if len(c.Columns) > 0 {
+				return c
+			}
+		}
+	}
+	for _, i := range t.Indexes {
+		if strings.Contains(i.Def, "PRIMARY") && len(i.Columns) > 0 {
+			return &schema.Constraint{
+				Name:    i.Name,
+				Type:    "PRIMARY KEY",
+				Def:     fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(i.Columns, ", ")),
+				Table:   &t.Name,
+				Columns: i.Columns,
+			}
+		}
+	}
+	return nil
+}
+
+// ConstraintDef return the definition of the primary key, unique or check constraint in CREATE TABLE or ALTER TABLE.
+// If the constraint can not be written in the dialect, return false.
+func (d Dialect) ConstraintDef(c *schema.Constraint) (string, bool) {
+	name := ""
+	if c.Name != "" {
+		name = fmt.Sprintf("CONSTRAINT %s ", d.Quote(c.Name))
+	}
+	switch strings.ToUpper(c.Type) {
+	case "PRIMARY KEY", "P":
+		if d == MySQL {
+			name = ""
+		}
+		return fmt.Sprintf("%sPRIMARY KEY (%s)", name, d.Columns(nil, c.Columns)), true
+	case "UNIQUE", "U", "UNIQUE KEY":
+		if len(c.Columns) == 0 {
+			return "", false
+		}
+		return fmt.Sprintf("%sUNIQUE (%s)", name, d.Columns(nil, c.Columns)), true
+	case "CHECK", "C":
+		if !strings.HasPrefix(strings.ToUpper(c.Def), "CHECK") {
+			return "", false
+		}
+		// type casts of Postgres can not be written in other dialects
+		if d != Postgres && strings.Contains(c.Def, "::") {
+			return "", false
+		}
+		return fmt.Sprintf("%s%s", name, c.Def), true
+	}
+	return "", false
+}
+
+// ForeignKeyDef return the definition of the foreign key of the relation
+func (d Dialect) ForeignKeyDef(r *schema.Relation) string {
+	cols := []string{}
+	for _, c := range r.Columns {
+		cols = append(cols, c.Name)
+	}
+	pcols := []string{}
+	for _, c := range r.ParentColumns {
+		pcols = append(pcols, c.Name)
+	}
+	def := fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)", d.Quote(ForeignKeyName(r)), d.Columns(nil, cols), d.TableName(r.ParentTable.Name), d.Columns(nil, pcols))
+	for _, m := range actionRe.FindAllStringSubmatch(r.Def, -1) {
+		def = fmt.Sprintf("%s ON %s %s", def, strings.ToUpper(m[1]), strings.ToUpper(m[2]))
+	}
+	return def
+}
+
+// ForeignKeyName return the name of the foreign key constraint of the relation
+func ForeignKeyName(r *schema.Relation) string {
+	for _, c := range r.Table.Constraints {
+		if c.Def == r.Def && c.Name != "" {
+			return c.Name
+		}
+	}
+	cols := []string{}
+	for _, c := range r.Columns {
+		cols = append(cols, c.Name)
+	}
+	return fmt.Sprintf("%s_%s_fkey", baseName(r.Table.Name), strings.Join(cols, "_"))
+}
+
+// CreateTable return CREATE TABLE statement. Foreign keys of relations are written inline.
+func (d Dialect) CreateTable(t *schema.Table, relations []*schema.Relation) string {
+	defs := []string{}
+	pk := PrimaryKey(t)
+	inlinePK := false
+	for _, c := range t.Columns {
+		def := d.ColumnDef(c)
+		// SQLite requires INTEGER PRIMARY KEY for AUTOINCREMENT
+		if d == SQLite && AutoIncrement(c) && pk != nil && len(pk.Columns) == 1 && pk.Columns[0] == c.Name {
+			def = fmt.Sprintf("%s INTEGER PRIMARY KEY AUTOINCREMENT", d.Quote(c.Name))
+			if !c.Nullable {
+				def = def + " NOT NULL"
+			}
+			inlinePK = true
+		}
+		defs = append(defs, def)
+	}
+	if pk != nil && !inlinePK {
+		if def, ok := d.ConstraintDef(pk); ok {
+			defs = append(defs, def)
+		}
+	}
+	for _, c := range t.Constraints {
+		switch strings.ToUpper(c.Type) {
+		case "PRIMARY KEY", "P":
+			continue
+		}
+		if def, ok := d.ConstraintDef(c); ok {
+			defs = append(defs, def)
+		}
+	}
+	for _, r := range relations {
+		defs = append(defs, d.ForeignKeyDef(r))
+	}
+	stmt := fmt.Sprintf("CREATE TABLE %s (\n  %s\n)", d.TableName(t.Name), strings.Join(defs, ",\n  "))
+	if d == MySQL && t.Comment != "" {
+		stmt = fmt.Sprintf("%s COMMENT=%s", stmt, d.QuoteString(t.Comment))
+	}
+	return stmt + ";"
+}
+
+// Indexes return indexes of the table that are not created by constraints
+func Indexes(t *schema.Table) []*schema.Index {
+	constraints := map[string]struct{}{}
+	for _, c := range t.Constraints {
+		constraints[c.Name] = struct{}{}
+	}
+	indexes := []*schema.Index{}
+	for _, i := range t.Indexes {
+		if _, ok := constraints[i.Name]; ok {
+			continue
+		}
+		if strings.Contains(i.Def, "PRIMARY") || len(i.Columns) == 0 {
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+// CreateIndex return CREATE INDEX statement
+func (d Dialect) CreateIndex(t *schema.Table, i *schema.Index) string {
+	unique := ""
+	if strings.Contains(strings.ToUpper(i.Def), "UNIQUE") {
+		unique = "UNIQUE "
+	}
+	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s);", unique, d.Quote(i.Name), d.TableName(t.Name), d.Columns(t, i.Columns))
+}
+
+// DropIndex return DROP INDEX statement
+func (d Dialect) DropIndex(t *schema.Table, i *schema.Index) string {
+	if d == MySQL {
+		return fmt.Sprintf("DROP INDEX %s ON %s;", d.Quote(i.Name), d.TableName(t.Name))
+	}
+	return fmt.Sprintf("DROP INDEX %s;", d.IndexName(t, i.Name))
+}
+
+// Comments return statements ( or SQL comments for SQLite ) of the table comment and the column comments.
+// MySQL comments are written in CREATE TABLE.
+func (d Dialect) Comments(t *schema.Table) []string {
+	stmts := []string{}
+	switch d {
+	case Postgres:
+		if t.Comment != "" {
+			stmts = append(stmts, fmt.Sprintf("COMMENT ON TABLE %s IS %s;", d.TableName(t.Name), d.QuoteString(t.Comment)))
+		}
+		for _, c := range t.Columns {
+			if c.Comment != "" {
+				stmts = append(stmts, d.ColumnComment(t, c))
+			}
+		}
+	case SQLite:
+		if t.Comment != "" {
+			stmts = append(stmts, fmt.Sprintf("-- %s: %s", t.Name, strings.ReplaceAll(t.Comment, "\n", " ")))
+		}
+		for _, c := range t.Columns {
+			if c.Comment != "" {
+				stmts = append(stmts, fmt.Sprintf("-- %s.%s: %s", t.Name, c.Name, strings.ReplaceAll(c.Comment, "\n", " ")))
+			}
+		}
+	}
+	return stmts
+}
+
+// ColumnComment return COMMENT ON COLUMN statement ( Postgres only )
+func (d Dialect) ColumnComment(t *schema.Table, c *schema.Column) string {
+	v := "NULL"
+	if c.Comment != "" {
+		v = d.QuoteString(c.Comment)
+	}
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", d.TableName(t.Name), d.Quote(c.Name), v)
+}
+
+func baseName(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return nonWordRe.ReplaceAllString(name, "_")
+}
@@ -1,6 +1,7 @@
 package dot
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"text/template"
@@ -58,16 +59,64 @@ func (d *Dot) tableTemplate() (string, error) {
 	}
 }
 
+func (d *Dot) lineageTemplate() (string, error) {
+	if len(d.config.Templates.Dot.Lineage) > 0 {
+		tb, err := os.ReadFile(d.config.Templates.Dot.Lineage)
+		if err != nil {
+			return string(tb), errors.WithStack(err)
+		}
+		return string(tb), nil
+	} else {
+		ts, err := d.box.FindString("lineage.dot.tmpl")
+		if err != nil {
+			return ts, errors.WithStack(err)
+		}
+		return ts, nil
+	}
+}
+
+// Position is the position of a table in ER diagram ( inches )
+type Position struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
 // OutputSchema output dot format for full relation.
 func (d *Dot) OutputSchema(wr io.Writer, s *schema.Schema) error {
+	return d.OutputSchemaWithPositions(wr, s, nil)
+}
+
+// OutputSchemaWithPositions output dot format for full relation with tables pinned to positions.
+func (d *Dot) OutputSchemaWithPositions(wr io.Writer, s *schema.Schema, positions map[string]*Position) error {
 	ts, err := d.schemaTemplate()
 	if err != nil {
 		return errors.WithStack(err)
 	}
+	tables := s.Tables
+	relations := s.Relations
+	if d.config.ER.Layout.Enabled {
+		tables = output.SeededTables(tables, d.config.ER.Layout.Seed)
+		relations = output.SeededRelations(relations, d.config.ER.Layout.Seed)
+	}
+	clusters, err := output.GroupTables(tables, d.config.ER.GroupBy)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if positions == nil {
+		positions = map[string]*Position{}
+	}
 	tmpl := template.Must(template.New(s.Name).Funcs(output.Funcs(&d.config.MergedDict)).Parse(ts))
 	err = tmpl.Execute(wr, map[string]interface{}{
 		"Schema":      s,
+		"Tables":      tables,
+		"Relations":   output.NewRelations(relations),
+		"Clusters":    clusters,
+		"Positions":   positions,
+		"pinned":      len(positions) > 0,
+		"seed":        d.config.ER.Layout.Seed,
 		"showComment": d.config.ER.Comment,
+		"link":        d.config.ER.Link,
+		"baseUrl":     d.config.BaseUrl,
 	})
 	if err != nil {
 		return errors.WithStack(err)
@@ -78,7 +127,12 @@ func (d *Dot) OutputSchema(wr io.Writer, s *schema.Schema) error {
 
 // OutputTable output dot format for table.
 func (d *Dot) OutputTable(wr io.Writer, t *schema.Table) error {
-	tables, relations, err := t.CollectTablesAndRelations(*d.config.ER.Distance, true)
+	direction, err := schema.ParseDirection(d.config.ER.Direction)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	tables, relations := t.CollectRelatedTables(*d.config.ER.Distance, direction)
+	clusters, err := output.GroupTables(tables, d.config.ER.GroupBy)
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -91,8 +145,11 @@ func (d *Dot) OutputTable(wr io.Writer, t *schema.Table) error {
 	err = tmpl.Execute(wr, map[string]interface{}{
 		"Table":       tables[0],
 		"Tables":      tables[1:],
-		"Relations":   relations,
+		"Relations":   output.NewRelations(relations),
+		"Clusters":    clusters,
 		"showComment": d.config.ER.Comment,
+		"link":        d.config.ER.Link,
+		"baseUrl":     d.config.BaseUrl,
 	})
 	if err != nil {
 		return errors.WithStack(err)
@@ -100,3 +157,83 @@ func (d *Dot) OutputTable(wr io.Writer, t *schema.Table) error {
 
 	return nil
 }
+
+// LineageEdge is the edge from an upstream column to a downstream column
+type LineageEdge struct {
+	Table        string
+	Column       string
+	ParentTable  string
+	ParentColumn string
+}
+
+// OutputLineage output dot format for column lineage of table.
+func (d *Dot) OutputLineage(wr io.Writer, t *schema.Table) error {
+	tables, edges := collectLineage(t)
+
+	ts, err := d.lineageTemplate()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	tmpl := template.Must(template.New(t.Name).Funcs(output.Funcs(&d.config.MergedDict)).Parse(ts))
+	err = tmpl.Execute(wr, map[string]interface{}{
+		"Table":       t,
+		"Tables":      tables,
+		"Edges":       edges,
+		"showComment": d.config.ER.Comment,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// collectLineage collect upstream tables ( with only columns in lineage ) and edges of table, following upstream views.
+func collectLineage(t *schema.Table) ([]*schema.Table, []*LineageEdge) {
+	tables := []*schema.Table{}
+	edges := []*LineageEdge{}
+	tableMap := map[string]*schema.Table{t.Name: t}
+	columnSet := map[string]struct{}{}
+	edgeSet := map[LineageEdge]struct{}{}
+
+	var walk func(table string, c *schema.Column)
+	walk = func(table string, c *schema.Column) {
+		for _, l := range c.Lineage {
+			e := LineageEdge{
+				Table:        table,
+				Column:       c.Name,
+				ParentTable:  l.Table.Name,
+				ParentColumn: l.Column.Name,
+			}
+			if _, ok := edgeSet[e]; ok {
+				continue
+			}
+			edgeSet[e] = struct{}{}
+			edges = append(edges, &e)
+
+			ut, ok := tableMap[l.Table.Name]
+			if !ok {
+				ut = &schema.Table{
+					Name:     l.Table.Name,
+					Type:     l.Table.Type,
+					Comment:  l.Table.Comment,
+					External: l.Table.External,
+				}
+				tableMap[l.Table.Name] = ut
+				tables = append(tables, ut)
+			}
+			key := fmt.Sprintf("%s.%s", l.Table.Name, l.Column.Name)
+			if _, ok := columnSet[key]; ok || ut == t {
+				continue
+			}
+			columnSet[key] = struct{}{}
+			ut.Columns = append(ut.Columns, l.Column)
+			walk(l.Table.Name, l.Column)
+		}
+	}
+	for _, c := range t.Columns {
+		walk(t.Name, c)
+	}
+
+	return tables, edges
+}
@@ -37,6 +37,77 @@ func TestOutputSchema(t *testing.T) {
 	}
 }
 
+func TestOutputSchemaCompositeRelation(t *testing.T) {
+	s := newTestSchema()
+	c, err := config.New()
+	if err != nil {
+		t.Error(err)
+	}
+	err = c.LoadConfigFile(filepath.Join(testdataDir(), "out_test_tbls.yml"))
+	if err != nil {
+		t.Error(err)
+	}
+	ta := s.Tables[0]
+	tb := s.Tables[1]
+	r := &schema.Relation{
+		Table:         ta,
+		Columns:       ta.Columns,
+		ParentTable:   tb,
+		ParentColumns: tb.Columns,
+		Def:           "FOREIGN KEY (a, a2) REFERENCES b(b, b2)",
+	}
+	ta.Columns[1].Nullable = true
+	s.Relations = []*schema.Relation{r}
+	err = c.MergeAdditionalData(s)
+	if err != nil {
+		t.Error(err)
+	}
+	o := New(c)
+	buf := &bytes.Buffer{}
+	err = o.OutputSchema(buf, s)
+	if err != nil {
+		t.Error(err)
+	}
+	want, _ := os.ReadFile(filepath.Join(testdataDir(), "dot_test_schema_composite.dot.golden"))
+	got := buf.String()
+	if got != string(want) {
+		t.Errorf("got %v\nwant %v", got, string(want))
+	}
+}
+
+func TestOutputSchemaWithPositions(t *testing.T) {
+	s := newTestSchema()
+	c, err := config.New()
+	if err != nil {
+		t.Error(err)
+	}
+	err = c.LoadConfigFile(filepath.Join(testdataDir(), "out_test_tbls.yml"))
+	if err != nil {
+		t.Error(err)
+	}
+	err = c.MergeAdditionalData(s)
+	if err != nil {
+		t.Error(err)
+	}
+	c.ER.Layout.Enabled = true
+	c.ER.Layout.Seed = 1
+	c.ER.GroupBy = "label"
+	s.Tables[0].Labels = schema.Labels{&schema.Label{Name: "blog"}}
+	o := New(c)
+	buf := &bytes.Buffer{}
+	err = o.OutputSchemaWithPositions(buf, s, map[string]*Position{
+		"a": &Position{X: 1.5, Y: 2},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	want, _ := os.ReadFile(filepath.Join(testdataDir(), "dot_test_schema_layout.dot.golden"))
+	got := buf.String()
+	if got != string(want) {
+		t.Errorf("got %v\nwant %v", got, string(want))
+	}
+}
+
 func TestOutputSchemaTemplate(t *testing.T) {
 	s := newTestSchema()
 	c, err := config.New()
@@ -94,6 +165,34 @@ func TestOutputTable(t *testing.T) {
 	}
 }
 
+func TestOutputTableLink(t *testing.T) {
+	s := newTestSchema()
+	c, err := config.New()
+	if err != nil {
+		t.Error(err)
+	}
+	err = c.LoadConfigFile(filepath.Join(testdataDir(), "out_test_tbls.yml"))
+	if err != nil {
+		t.Error(err)
+	}
+	c.ER.Link = true
+	c.BaseUrl = "https://example.com/dbdoc/"
+	err = c.MergeAdditionalData(s)
+	if err != nil {
+		t.Error(err)
+	}
+	ta := s.Tables[0]
+
+	o := New(c)
+	buf := &bytes.Buffer{}
+	_ = o.OutputTable(buf, ta)
+	want, _ := os.ReadFile(filepath.Join(testdataDir(), "dot_test_a_link.dot.golden"))
+	got := buf.String()
+	if got != string(want) {
+		t.Errorf("got %v\nwant %v", got, string(want))
+	}
+}
+
 func TestOutputTableTemplate(t *testing.T) {
 	s := newTestSchema()
 	c, err := config.New()
@@ -123,6 +222,52 @@ func TestOutputTableTemplate(t *testing.T) {
 	}
 }
 
+func TestOutputLineage(t *testing.T) {
+	s := newTestSchema()
+	c, err := config.New()
+	if err != nil {
+		t.Error(err)
+	}
+	err = c.LoadConfigFile(filepath.Join(testdataDir(), "out_test_tbls.yml"))
+	if err != nil {
+		t.Error(err)
+	}
+	ta := s.Tables[0]
+	tb := s.Tables[1]
+	v := &schema.Table{
+		Name: "v",
+		Type: "VIEW",
+		Columns: []*schema.Column{
+			&schema.Column{
+				Name: "x",
+				Type: "text",
+				Lineage: []*schema.Lineage{
+					&schema.Lineage{Table: ta, Column: ta.Columns[0]},
+					&schema.Lineage{Table: tb, Column: tb.Columns[1]},
+				},
+			},
+			&schema.Column{
+				Name: "y",
+				Type: "text",
+				Lineage: []*schema.Lineage{
+					&schema.Lineage{Table: &schema.Table{Name: "ext", External: true}, Column: &schema.Column{Name: "e"}},
+				},
+			},
+		},
+	}
+
+	o := New(c)
+	buf := &bytes.Buffer{}
+	if err := o.OutputLineage(buf, v); err != nil {
+		t.Error(err)
+	}
+	want, _ := os.ReadFile(filepath.Join(testdataDir(), "dot_test_lineage.dot.golden"))
+	got := buf.String()
+	if got != string(want) {
+		t.Errorf("got %v\nwant %v", got, string(want))
+	}
+}
+
 func testdataDir() string {
 	wd, _ := os.Getwd()
 	dir, _ := filepath.Abs(filepath.Join(filepath.Dir(filepath.Dir(wd)), "testdata"))
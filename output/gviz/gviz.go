@@ -4,13 +4,18 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+	"text/template"
 
 	"github.com/beta/freetype/truetype"
 	"github.com/goccy/go-graphviz"
+	"github.com/gobuffalo/packr/v2"
 	"github.com/k1LoW/ffff"
 	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/output"
 	"github.com/tmdc-io/tbls/output/dot"
 	"github.com/tmdc-io/tbls/schema"
 	"github.com/pkg/errors"
@@ -21,8 +26,10 @@ import (
 
 // Gviz struct
 type Gviz struct {
-	config *config.Config
-	dot    *dot.Dot
+	config    *config.Config
+	dot       *dot.Dot
+	box       *packr.Box
+	renderCmd []string
 }
 
 // New return Gviz
@@ -30,11 +37,21 @@ func New(c *config.Config) *Gviz {
 	return &Gviz{
 		config: c,
 		dot:    dot.New(c),
+		box:    packr.New("gviz", "./html"),
 	}
 }
 
 // OutputSchema output dot format for full relation.
+// Tables are not pinned to the layout when they are grouped into clusters, because graphviz ignores clusters with pinned positions.
 func (g *Gviz) OutputSchema(wr io.Writer, s *schema.Schema) error {
+	if g.config.ER.Layout.Enabled && g.config.ER.GroupBy == "" {
+		l, err := LoadLayout(g.config.LayoutPath())
+		if err != nil {
+			return err
+		}
+		_, err = g.OutputSchemaWithLayout(wr, s, l)
+		return err
+	}
 	buf := &bytes.Buffer{}
 	if err := g.dot.OutputSchema(buf, s); err != nil {
 		return errors.WithStack(err)
@@ -42,6 +59,56 @@ func (g *Gviz) OutputSchema(wr io.Writer, s *schema.Schema) error {
 	return g.render(wr, buf.Bytes())
 }
 
+// OutputSchemaWithLayout output ER diagram of the schema with tables pinned to the positions of the layout.
+// Tables not in the layout are laid out around the pinned tables. It return the layout with positions of all tables.
+func (g *Gviz) OutputSchemaWithLayout(wr io.Writer, s *schema.Schema, l *Layout) (*Layout, error) {
+	positions := map[string]*dot.Position{}
+	unpinned := false
+	for _, t := range s.Tables {
+		if p, ok := l.Tables[t.Name]; ok {
+			positions[t.Name] = p
+			continue
+		}
+		unpinned = true
+	}
+
+	buf := &bytes.Buffer{}
+	if err := g.dot.OutputSchemaWithPositions(buf, s, positions); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if unpinned {
+		layout := graphviz.NEATO
+		if len(positions) == 0 {
+			layout = graphviz.DOT
+		}
+		plain := &bytes.Buffer{}
+		if err := g.renderWith(plain, buf.Bytes(), graphviz.Format("plain"), layout); err != nil {
+			return nil, err
+		}
+		rendered, err := parsePlain(plain.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range s.Tables {
+			if _, ok := positions[t.Name]; ok {
+				continue
+			}
+			if p, ok := rendered[t.Name]; ok {
+				positions[t.Name] = p
+			}
+		}
+		buf.Reset()
+		if err := g.dot.OutputSchemaWithPositions(buf, s, positions); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	if err := g.renderWith(wr, buf.Bytes(), graphviz.Format(g.config.ER.Format), graphviz.NEATO); err != nil {
+		return nil, err
+	}
+	return &Layout{Tables: positions}, nil
+}
+
 // OutputTable output dot format for table.
 func (g *Gviz) OutputTable(wr io.Writer, t *schema.Table) error {
 	buf := &bytes.Buffer{}
@@ -52,15 +119,92 @@ func (g *Gviz) OutputTable(wr io.Writer, t *schema.Table) error {
 	return g.render(wr, buf.Bytes())
 }
 
-func (g *Gviz) render(wr io.Writer, b []byte) (e error) {
-	gviz := graphviz.New()
+// OutputLineage output column lineage of table.
+func (g *Gviz) OutputLineage(wr io.Writer, t *schema.Table) error {
+	buf := &bytes.Buffer{}
+	err := g.dot.OutputLineage(buf, t)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return g.render(wr, buf.Bytes())
+}
+
+// OutputHTML output HTML embedding SVG with pan and zoom.
+func (g *Gviz) OutputHTML(wr io.Writer, name string, svg []byte) error {
+	ts, err := g.box.FindString("svg.html.tmpl")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	// strip XML declaration and DOCTYPE
+	if i := bytes.Index(svg, []byte("<svg")); i > 0 {
+		svg = svg[i:]
+	}
+	tmpl := template.Must(template.New(name).Funcs(output.Funcs(&g.config.MergedDict)).Parse(ts))
+	err = tmpl.Execute(wr, map[string]interface{}{
+		"Name": name,
+		"SVG":  string(svg),
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// SetRenderCommand set the command that renders dot sources in a separate process ( e.g. `tbls render` ).
+// The command reads the dot source from stdin and writes the image to stdout, so that diagrams are rendered in parallel.
+func (g *Gviz) SetRenderCommand(name string, args ...string) {
+	g.renderCmd = append([]string{name}, args...)
+}
+
+func (g *Gviz) render(wr io.Writer, b []byte) error {
+	return g.renderWith(wr, b, graphviz.Format(g.config.ER.Format), "")
+}
+
+func (g *Gviz) renderWith(wr io.Writer, b []byte, format graphviz.Format, layout graphviz.Layout) error {
+	if len(g.renderCmd) == 0 {
+		return Render(wr, b, format, layout, g.config.ER.Font)
+	}
+	args := append([]string{}, g.renderCmd[1:]...)
+	args = append(args, "--format", string(format))
+	if layout != "" {
+		args = append(args, "--layout", string(layout))
+	}
 	if g.config.ER.Font != "" {
-		faceFunc, err := getFaceFunc(g.config.ER.Font)
+		args = append(args, "--font", g.config.ER.Font)
+	}
+	stderr := &bytes.Buffer{}
+	cmd := exec.Command(g.renderCmd[0], args...) // #nosec
+	cmd.Stdin = bytes.NewReader(b)
+	cmd.Stdout = wr
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "failed to render: %s", strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// renderMu serializes rendering in a process because the graphviz C library is not goroutine-safe.
+var renderMu sync.Mutex
+
+// Render render the dot source to the format with the layout engine ( default: the layout of the source ) and the font.
+func Render(wr io.Writer, b []byte, format graphviz.Format, layout graphviz.Layout, fontName string) (e error) {
+	var faceFunc func(size float64) (font.Face, error)
+	if fontName != "" {
+		var err error
+		faceFunc, err = getFaceFunc(fontName)
 		if err != nil {
 			return errors.WithStack(err)
 		}
+	}
+	renderMu.Lock()
+	defer renderMu.Unlock()
+	gviz := graphviz.New()
+	if faceFunc != nil {
 		gviz.SetFontFace(faceFunc)
 	}
+	if layout != "" {
+		gviz.SetLayout(layout)
+	}
 	graph, err := graphviz.ParseBytes(b)
 	if err != nil {
 		return errors.WithStack(err)
@@ -73,7 +217,7 @@ func (g *Gviz) render(wr io.Writer, b []byte) (e error) {
 			e = errors.WithStack(err)
 		}
 	}()
-	if err := gviz.Render(graph, graphviz.Format(g.config.ER.Format), wr); err != nil {
+	if err := gviz.Render(graph, format, wr); err != nil {
 		return errors.WithStack(err)
 	}
 	return nil
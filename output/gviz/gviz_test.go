@@ -2,11 +2,15 @@ package gviz
 
 import (
 	"bytes"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/goccy/go-graphviz"
 	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/output/dot"
 	"github.com/tmdc-io/tbls/schema"
 )
 
@@ -70,6 +74,86 @@ func TestOutputTable(t *testing.T) {
 	}
 }
 
+func TestOutputSchemaWithLayoutAndGroupBy(t *testing.T) {
+	s := newTestSchema()
+	s.Tables[0].Name = "x_a"
+	s.Tables[1].Name = "x_b"
+	c, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.LoadOption(config.ERFormat("svg")); err != nil {
+		t.Fatal(err)
+	}
+	c.ER.GroupBy = "prefix"
+	c.ER.Layout.Enabled = true
+	c.ER.Layout.Path = filepath.Join(t.TempDir(), "schema.layout.json")
+	l := &Layout{Tables: map[string]*dot.Position{
+		"x_a": &dot.Position{X: 1, Y: 1},
+		"x_b": &dot.Position{X: 4, Y: 1},
+	}}
+	if err := l.Save(c.ER.Layout.Path); err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	if err := New(c).OutputSchema(buf, s); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `class="cluster"`) {
+		t.Errorf("got %v\nwant cluster", buf.String())
+	}
+}
+
+func TestRenderCommand(t *testing.T) {
+	s := newTestSchema()
+	c, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.LoadOption(config.ERFormat("svg")); err != nil {
+		t.Fatal(err)
+	}
+	want := &bytes.Buffer{}
+	if err := New(c).OutputTable(want, s.Tables[0]); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TBLS_TEST_RENDER_HELPER", "1")
+	g := New(c)
+	g.SetRenderCommand(os.Args[0], "-test.run=TestRenderHelper", "--")
+	got := &bytes.Buffer{}
+	if err := g.OutputTable(got, s.Tables[0]); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("got %v\nwant %v", got.String(), want.String())
+	}
+}
+
+// TestRenderHelper is the render command of TestRenderCommand
+func TestRenderHelper(t *testing.T) {
+	if os.Getenv("TBLS_TEST_RENDER_HELPER") != "1" {
+		t.Skip()
+	}
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	format := ""
+	for i := 1; i < len(args)-1; i++ {
+		if args[i] == "--format" {
+			format = args[i+1]
+		}
+	}
+	b, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Render(os.Stdout, b, graphviz.Format(format), "", ""); err != nil {
+		t.Fatal(err)
+	}
+	os.Exit(0)
+}
+
 func testdataDir() string {
 	wd, _ := os.Getwd()
 	dir, _ := filepath.Abs(filepath.Join(filepath.Dir(filepath.Dir(wd)), "testdata"))
@@ -0,0 +1,115 @@
+package gviz
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tmdc-io/tbls/output/dot"
+)
+
+// Layout is pinned positions of tables in the ER diagram of the schema
+type Layout struct {
+	Tables map[string]*dot.Position `json:"tables"`
+}
+
+// LoadLayout load the layout file. If it does not exist, return an empty layout.
+func LoadLayout(path string) (*Layout, error) {
+	l := &Layout{
+		Tables: map[string]*dot.Position{},
+	}
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return l, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+	if err := json.Unmarshal(b, l); err != nil {
+		return nil, errors.Wrapf(err, "invalid layout file %s", path)
+	}
+	if l.Tables == nil {
+		l.Tables = map[string]*dot.Position{}
+	}
+	return l, nil
+}
+
+// Save save the layout to the layout file
+func (l *Layout) Save(path string) error {
+	b, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil { // #nosec
+		return errors.WithStack(err)
+	}
+	if err := os.WriteFile(path, append(b, '\n'), 0644); err != nil { // #nosec
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// parsePlain parse positions of nodes from graphviz plain format output.
+func parsePlain(b []byte) (map[string]*dot.Position, error) {
+	positions := map[string]*dot.Position{}
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	sc.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		if !strings.HasPrefix(line, "node ") {
+			continue
+		}
+		name, rest, err := plainField(line[len("node "):])
+		if err != nil {
+			return nil, err
+		}
+		fields := strings.Fields(rest)
+		if len(fields) < 2 {
+			return nil, errors.Errorf("invalid plain format: %s", line)
+		}
+		x, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		y, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		positions[name] = &dot.Position{X: x, Y: y}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return positions, nil
+}
+
+// plainField return the first ( possibly double-quoted ) field and the rest.
+func plainField(s string) (string, string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		i := strings.Index(s, " ")
+		if i < 0 {
+			return s, "", nil
+		}
+		return s[:i], s[i+1:], nil
+	}
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				sb.WriteByte(s[i])
+			}
+		case '"':
+			return sb.String(), strings.TrimLeft(s[i+1:], " "), nil
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return "", "", errors.Errorf("invalid plain format: %s", s)
+}
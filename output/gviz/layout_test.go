@@ -0,0 +1,56 @@
+package gviz
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/tmdc-io/tbls/output/dot"
+)
+
+func TestParsePlain(t *testing.T) {
+	in := `graph 1 4.5 3.25
+node users 1.25 2.5 2.1 1.4 <<table>> solid none black lightgrey
+node "public.user options" 3 0.75 2.1 1.4 "user \"options\"" solid none black lightgrey
+edge "public.user options" users 4 3 1.5 2.1 1.8 solid black
+stop
+`
+	got, err := parsePlain([]byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]*dot.Position{
+		"users":               &dot.Position{X: 1.25, Y: 2.5},
+		"public.user options": &dot.Position{X: 3, Y: 0.75},
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %v\nwant %v", len(got), len(want))
+	}
+	for k, w := range want {
+		g, ok := got[k]
+		if !ok || *g != *w {
+			t.Errorf("%s: got %v\nwant %v", k, g, w)
+		}
+	}
+}
+
+func TestLayoutSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "docs", "schema.layout.json")
+	l, err := LoadLayout(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Tables) != 0 {
+		t.Errorf("got %v\nwant %v", len(l.Tables), 0)
+	}
+	l.Tables["users"] = &dot.Position{X: 1, Y: 2}
+	if err := l.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadLayout(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := got.Tables["users"]; p == nil || p.X != 1 || p.Y != 2 {
+		t.Errorf("got %v\nwant %v", p, l.Tables["users"])
+	}
+}
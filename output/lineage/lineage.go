@@ -0,0 +1,206 @@
+package lineage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/gobuffalo/packr/v2"
+	"github.com/pkg/errors"
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/output"
+	"github.com/tmdc-io/tbls/schema"
+)
+
+// Directions of tables from the focal table
+const (
+	Upstream   = "upstream"
+	Downstream = "downstream"
+)
+
+// Lineage struct
+type Lineage struct {
+	config *config.Config
+	schema *schema.Schema
+	format string
+	box    *packr.Box
+}
+
+// Node is a table in the view dependency graph
+type Node struct {
+	Table     *schema.Table
+	Focal     bool
+	Direction string
+	Distance  int
+}
+
+// Edge is the dependency of a view on a referenced table
+type Edge struct {
+	Table           *schema.Table
+	ReferencedTable *schema.Table
+}
+
+// New return Lineage. format is one of dot, mermaid and json
+func New(c *config.Config, s *schema.Schema, format string) *Lineage {
+	return &Lineage{
+		config: c,
+		schema: s,
+		format: format,
+		box:    packr.New("lineage", "./templates"),
+	}
+}
+
+// OutputSchema output view dependency graph of all tables.
+func (l *Lineage) OutputSchema(wr io.Writer, s *schema.Schema) error {
+	nodes := []*Node{}
+	edges := []*Edge{}
+	encountered := map[string]struct{}{}
+	add := func(t *schema.Table) {
+		if _, ok := encountered[t.Name]; ok {
+			return
+		}
+		encountered[t.Name] = struct{}{}
+		nodes = append(nodes, &Node{Table: t})
+	}
+	for _, t := range s.Tables {
+		for _, rt := range t.ReferencedTables {
+			edges = append(edges, &Edge{Table: t, ReferencedTable: rt})
+		}
+	}
+	for _, t := range s.Tables {
+		if len(t.ReferencedTables) > 0 || len(s.ReferencedBy(t)) > 0 {
+			add(t)
+		}
+	}
+	for _, e := range edges {
+		add(e.ReferencedTable)
+	}
+	return l.output(wr, s.Name, nodes, edges)
+}
+
+// OutputTable output view dependency graph around table.
+func (l *Lineage) OutputTable(wr io.Writer, t *schema.Table) error {
+	nodes, edges := l.collect(t, *l.config.ER.Distance)
+	return l.output(wr, t.Name, nodes, edges)
+}
+
+// collect collect upstream and downstream tables of table within distance.
+func (l *Lineage) collect(t *schema.Table, distance int) ([]*Node, []*Edge) {
+	nodes := []*Node{&Node{Table: t, Focal: true}}
+	edges := []*Edge{}
+	encounteredT := map[string]struct{}{t.Name: struct{}{}}
+	encounteredE := map[string]struct{}{}
+	addEdge := func(e *Edge) {
+		key := fmt.Sprintf("%s->%s", e.ReferencedTable.Name, e.Table.Name)
+		if _, ok := encounteredE[key]; ok {
+			return
+		}
+		encounteredE[key] = struct{}{}
+		edges = append(edges, e)
+	}
+
+	for _, direction := range []string{Upstream, Downstream} {
+		current := []*schema.Table{t}
+		for d := 1; d <= distance && len(current) > 0; d++ {
+			next := []*schema.Table{}
+			for _, ct := range current {
+				var tables []*schema.Table
+				if direction == Upstream {
+					tables = ct.ReferencedTables
+				} else {
+					tables = l.schema.ReferencedBy(ct)
+				}
+				for _, tt := range tables {
+					if direction == Upstream {
+						addEdge(&Edge{Table: ct, ReferencedTable: tt})
+					} else {
+						addEdge(&Edge{Table: tt, ReferencedTable: ct})
+					}
+					if _, ok := encounteredT[tt.Name]; ok {
+						continue
+					}
+					encounteredT[tt.Name] = struct{}{}
+					nodes = append(nodes, &Node{Table: tt, Direction: direction, Distance: d})
+					next = append(next, tt)
+				}
+			}
+			current = next
+		}
+	}
+
+	return nodes, edges
+}
+
+func (l *Lineage) output(wr io.Writer, name string, nodes []*Node, edges []*Edge) error {
+	switch l.format {
+	case "json":
+		return outputJSON(wr, nodes, edges)
+	case "dot", "mermaid":
+		ts, err := l.box.FindString(fmt.Sprintf("lineage.%s.tmpl", l.format))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		ids := map[string]string{}
+		for i, n := range nodes {
+			ids[n.Table.Name] = fmt.Sprintf("t%d", i)
+		}
+		tmpl := template.Must(template.New(name).Funcs(output.Funcs(&l.config.MergedDict)).Parse(ts))
+		err = tmpl.Execute(wr, map[string]interface{}{
+			"Name":  name,
+			"Nodes": nodes,
+			"Edges": edges,
+			"Ids":   ids,
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		return nil
+	default:
+		return errors.Errorf("unsupported lineage format '%s'", l.format)
+	}
+}
+
+type jsonNode struct {
+	Name      string `json:"name"`
+	Type      string `json:"type,omitempty"`
+	External  bool   `json:"external,omitempty"`
+	Focal     bool   `json:"focal,omitempty"`
+	Direction string `json:"direction,omitempty"`
+	Distance  int    `json:"distance,omitempty"`
+}
+
+type jsonEdge struct {
+	Table           string `json:"table"`
+	ReferencedTable string `json:"referenced_table"`
+}
+
+func outputJSON(wr io.Writer, nodes []*Node, edges []*Edge) error {
+	jn := []jsonNode{}
+	for _, n := range nodes {
+		jn = append(jn, jsonNode{
+			Name:      n.Table.Name,
+			Type:      n.Table.Type,
+			External:  n.Table.External,
+			Focal:     n.Focal,
+			Direction: n.Direction,
+			Distance:  n.Distance,
+		})
+	}
+	je := []jsonEdge{}
+	for _, e := range edges {
+		je = append(je, jsonEdge{
+			Table:           e.Table.Name,
+			ReferencedTable: e.ReferencedTable.Name,
+		})
+	}
+	encoder := json.NewEncoder(wr)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(map[string]interface{}{
+		"tables": jn,
+		"edges":  je,
+	}); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package lineage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/schema"
+)
+
+func TestOutputSchema(t *testing.T) {
+	s := newTestSchema()
+	c, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := New(c, s, "dot")
+	buf := &bytes.Buffer{}
+	if err := o.OutputSchema(buf, s); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := os.ReadFile(filepath.Join(testdataDir(), "lineage_test_schema.dot.golden"))
+	got := buf.String()
+	if got != string(want) {
+		t.Errorf("got %v\nwant %v", got, string(want))
+	}
+}
+
+func TestOutputTable(t *testing.T) {
+	tests := []struct {
+		format   string
+		table    string
+		distance int
+		golden   string
+	}{
+		{"mermaid", "posts", 2, "lineage_test_posts.mermaid.golden"},
+		{"json", "post_users", 1, "lineage_test_post_users.json.golden"},
+	}
+	for _, tt := range tests {
+		s := newTestSchema()
+		c, err := config.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.LoadOption(config.Distance(tt.distance)); err != nil {
+			t.Fatal(err)
+		}
+		ta, err := s.FindTableByName(tt.table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		o := New(c, s, tt.format)
+		buf := &bytes.Buffer{}
+		if err := o.OutputTable(buf, ta); err != nil {
+			t.Fatal(err)
+		}
+		want, _ := os.ReadFile(filepath.Join(testdataDir(), tt.golden))
+		got := buf.String()
+		if got != string(want) {
+			t.Errorf("got %v\nwant %v", got, string(want))
+		}
+	}
+}
+
+func testdataDir() string {
+	wd, _ := os.Getwd()
+	dir, _ := filepath.Abs(filepath.Join(filepath.Dir(filepath.Dir(wd)), "testdata"))
+	return dir
+}
+
+func newTestSchema() *schema.Schema {
+	posts := &schema.Table{
+		Name: "posts",
+		Type: "BASE TABLE",
+	}
+	users := &schema.Table{
+		Name: "users",
+		Type: "BASE TABLE",
+	}
+	logs := &schema.Table{
+		Name: "logs",
+		Type: "BASE TABLE",
+	}
+	postUsers := &schema.Table{
+		Name:             "post_users",
+		Type:             "VIEW",
+		ReferencedTables: []*schema.Table{posts, users},
+	}
+	summary := &schema.Table{
+		Name: "summary",
+		Type: "MATERIALIZED VIEW",
+		ReferencedTables: []*schema.Table{
+			postUsers,
+			&schema.Table{Name: "external.stats", External: true},
+		},
+	}
+	return &schema.Schema{
+		Name: "testschema",
+		Tables: []*schema.Table{
+			posts,
+			users,
+			logs,
+			postUsers,
+			summary,
+		},
+	}
+}
@@ -23,9 +23,11 @@ var mdEscRep = strings.NewReplacer("`", "\\`")
 
 // Md struct
 type Md struct {
-	config *config.Config
-	er     bool
-	box    *packr.Box
+	config  *config.Config
+	er      bool
+	lineage bool
+	usedBy  []*schema.Table
+	box     *packr.Box
 }
 
 // New return Md
@@ -76,10 +78,7 @@ func (m *Md) OutputSchema(wr io.Writer, s *schema.Schema) error {
 		return errors.WithStack(err)
 	}
 	tmpl := template.Must(template.New("index").Funcs(output.Funcs(&m.config.MergedDict)).Parse(ts))
-	templateData := m.makeSchemaTemplateData(s)
-	templateData["er"] = m.er
-	templateData["erFormat"] = m.config.ER.Format
-	templateData["baseUrl"] = m.config.BaseUrl
+	templateData := m.SchemaTemplateData(s)
 	err = tmpl.Execute(wr, templateData)
 	if err != nil {
 		return errors.WithStack(err)
@@ -94,10 +93,7 @@ func (m *Md) OutputTable(wr io.Writer, t *schema.Table) error {
 		return errors.WithStack(err)
 	}
 	tmpl := template.Must(template.New(t.Name).Funcs(output.Funcs(&m.config.MergedDict)).Parse(ts))
-	templateData := m.makeTableTemplateData(t)
-	templateData["er"] = m.er
-	templateData["erFormat"] = m.config.ER.Format
-	templateData["baseUrl"] = m.config.BaseUrl
+	templateData := m.TableTemplateData(t)
 
 	err = tmpl.Execute(wr, templateData)
 	if err != nil {
@@ -107,11 +103,39 @@ func (m *Md) OutputTable(wr io.Writer, t *schema.Table) error {
 	return nil
 }
 
-// Output generate markdown files.
-func Output(s *schema.Schema, c *config.Config, force bool) (e error) {
-	docPath := c.DocPath
+// SchemaTemplateData return the template data of the index page.
+func (m *Md) SchemaTemplateData(s *schema.Schema) map[string]interface{} {
+	templateData := m.makeSchemaTemplateData(s)
+	templateData["er"] = m.er
+	templateData["erFormat"] = m.config.ER.Format
+	templateData["baseUrl"] = m.config.BaseUrl
+	return templateData
+}
 
-	fullPath, err := filepath.Abs(docPath)
+// TableTemplateData return the template data of the table page.
+func (m *Md) TableTemplateData(t *schema.Table) map[string]interface{} {
+	templateData := m.makeTableTemplateData(t)
+	templateData["er"] = m.er
+	templateData["lineage"] = m.lineage
+	templateData["UsedBy"] = m.usedByLinks()
+	templateData["erFormat"] = m.config.ER.Format
+	templateData["baseUrl"] = m.config.BaseUrl
+	return templateData
+}
+
+// SetLineage set whether the table page embeds the lineage graph image.
+func (m *Md) SetLineage(lineage bool) {
+	m.lineage = lineage
+}
+
+// SetUsedBy set views that depend on the table.
+func (m *Md) SetUsedBy(tables []*schema.Table) {
+	m.usedBy = tables
+}
+
+// Output generate markdown files.
+func Output(s *schema.Schema, c *config.Config, force bool) error {
+	fullPath, err := filepath.Abs(c.DocPath)
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -120,40 +144,54 @@ func Output(s *schema.Schema, c *config.Config, force bool) (e error) {
 		return errors.New("output files already exists")
 	}
 
+	return OutputFiles(s, c, true, s.Tables)
+}
+
+// OutputFiles generate README.md ( if readme is true ) and markdown files of the tables.
+func OutputFiles(s *schema.Schema, c *config.Config, readme bool, tables []*schema.Table) (e error) {
+	docPath := c.DocPath
+
+	fullPath, err := filepath.Abs(docPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	err = os.MkdirAll(fullPath, 0755) // #nosec
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
 	// README.md
-	file, err := os.Create(filepath.Join(fullPath, "README.md"))
-	defer func() {
-		err := file.Close()
+	if readme {
+		file, err := os.Create(filepath.Join(fullPath, "README.md"))
+		defer func() {
+			err := file.Close()
+			if err != nil {
+				e = err
+			}
+		}()
 		if err != nil {
-			e = err
+			return errors.WithStack(err)
+		}
+		er := false
+		if _, err := os.Lstat(filepath.Join(fullPath, fmt.Sprintf("schema.%s", c.ER.Format))); err == nil {
+			er = true
 		}
-	}()
-	if err != nil {
-		return errors.WithStack(err)
-	}
-	er := false
-	if _, err := os.Lstat(filepath.Join(fullPath, fmt.Sprintf("schema.%s", c.ER.Format))); err == nil {
-		er = true
-	}
 
-	md := New(c, er)
+		md := New(c, er)
 
-	err = md.OutputSchema(file, s)
-	if err != nil {
-		return errors.WithStack(err)
+		err = md.OutputSchema(file, s)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		fmt.Printf("%s\n", filepath.Join(docPath, "README.md"))
 	}
-	fmt.Printf("%s\n", filepath.Join(docPath, "README.md"))
 
 	// tables
-	for _, t := range s.Tables {
+	err = output.Parallel(len(tables), c.Parallel, func(i int) error {
+		t := tables[i]
 		file, err := os.Create(filepath.Join(fullPath, fmt.Sprintf("%s.md", t.Name)))
 		if err != nil {
-			_ = file.Close()
 			return errors.WithStack(err)
 		}
 
@@ -163,17 +201,21 @@ func Output(s *schema.Schema, c *config.Config, force bool) (e error) {
 		}
 
 		md := New(c, er)
+		md.SetLineage(hasLineageImage(c, t, er))
+		md.SetUsedBy(s.ReferencedBy(t))
 
 		err = md.OutputTable(file, t)
 		if err != nil {
 			_ = file.Close()
 			return errors.WithStack(err)
 		}
+		return errors.WithStack(file.Close())
+	})
+	if err != nil {
+		return err
+	}
+	for _, t := range tables {
 		fmt.Printf("%s\n", filepath.Join(docPath, fmt.Sprintf("%s.md", t.Name)))
-		err = file.Close()
-		if err != nil {
-			return errors.WithStack(err)
-		}
 	}
 	return nil
 }
@@ -200,7 +242,7 @@ func DiffSchemas(s, s2 *schema.Schema, c, c2 *config.Config) (string, error) {
 	}
 	from := fmt.Sprintf("tbls doc %s", mdsnA)
 
-	mdsnB, err := c.MaskedDSN()
+	mdsnB, err := c2.MaskedDSN()
 	if err != nil {
 		return "", errors.WithStack(err)
 	}
@@ -351,6 +393,8 @@ func DiffSchemaAndDocs(docPath string, s *schema.Schema, c *config.Config) (stri
 		to := fmt.Sprintf("%s %s", mdsn, t.Name)
 
 		md := New(c, er)
+		md.SetLineage(hasLineageImage(c, t, er))
+		md.SetUsedBy(s.ReferencedBy(t))
 
 		err := md.OutputTable(b, t)
 		if err != nil {
@@ -641,6 +685,58 @@ func (m *Md) makeTableTemplateData(t *schema.Table) map[string]interface{} {
 		triggersData = append(triggersData, data)
 	}
 
+	// Relations
+	relationsData := [][]string{
+		[]string{
+			m.config.MergedDict.Lookup("Table"),
+			m.config.MergedDict.Lookup("Columns"),
+			m.config.MergedDict.Lookup("Parent Table"),
+			m.config.MergedDict.Lookup("Parent Columns"),
+			m.config.MergedDict.Lookup("Cardinality"),
+		},
+		[]string{"-----", "-------", "------------", "--------------", "-----------"},
+	}
+	rEncountered := map[*schema.Relation]struct{}{}
+	relations := []*schema.Relation{}
+	for _, c := range t.Columns {
+		for _, r := range c.ParentRelations {
+			if _, ok := rEncountered[r]; ok {
+				continue
+			}
+			rEncountered[r] = struct{}{}
+			relations = append(relations, r)
+		}
+	}
+	for _, c := range t.Columns {
+		for _, r := range c.ChildRelations {
+			if _, ok := rEncountered[r]; ok {
+				continue
+			}
+			rEncountered[r] = struct{}{}
+			relations = append(relations, r)
+		}
+	}
+	if !m.config.Format.RelationsTable {
+		relations = nil
+	}
+	for _, r := range output.NewRelations(relations) {
+		columns := []string{}
+		for _, c := range r.Columns {
+			columns = append(columns, c.Name)
+		}
+		parentColumns := []string{}
+		for _, c := range r.ParentColumns {
+			parentColumns = append(parentColumns, c.Name)
+		}
+		relationsData = append(relationsData, []string{
+			fmt.Sprintf("[%s](%s%s.md)", r.Table.Name, m.config.BaseUrl, r.Table.Name),
+			strings.Join(columns, ", "),
+			fmt.Sprintf("[%s](%s%s.md)", r.ParentTable.Name, m.config.BaseUrl, r.ParentTable.Name),
+			strings.Join(parentColumns, ", "),
+			fmt.Sprintf("%s : %s", m.config.MergedDict.Lookup(cardinalityWords[r.Cardinality]), m.config.MergedDict.Lookup(cardinalityWords[r.ParentCardinality])),
+		})
+	}
+
 	// Referenced Tables
 	referencedTables := []string{}
 	for _, rt := range t.ReferencedTables {
@@ -651,11 +747,24 @@ func (m *Md) makeTableTemplateData(t *schema.Table) map[string]interface{} {
 		referencedTables = append(referencedTables, fmt.Sprintf("[%s](%s%s.md)", rt.Name, m.config.BaseUrl, rt.Name))
 	}
 
+	// Column Lineage
+	lineageData := []string{}
+	for _, c := range t.Columns {
+		if len(c.Lineage) == 0 {
+			continue
+		}
+		lineageData = append(lineageData, fmt.Sprintf("- %s", c.Name))
+		lineageData = append(lineageData, m.lineageList(c.Lineage, 1, map[string]struct{}{
+			fmt.Sprintf("%s.%s", t.Name, c.Name): struct{}{},
+		})...)
+	}
+
 	if number {
 		columnsData = m.addNumberToTable(columnsData)
 		constraintsData = m.addNumberToTable(constraintsData)
 		indexesData = m.addNumberToTable(indexesData)
 		triggersData = m.addNumberToTable(triggersData)
+		relationsData = m.addNumberToTable(relationsData)
 	}
 
 	if adjust {
@@ -665,7 +774,9 @@ func (m *Md) makeTableTemplateData(t *schema.Table) map[string]interface{} {
 			"Constraints":      adjustTable(constraintsData),
 			"Indexes":          adjustTable(indexesData),
 			"Triggers":         adjustTable(triggersData),
+			"Relations":        adjustTable(relationsData),
 			"ReferencedTables": referencedTables,
+			"Lineage":          lineageData,
 		}
 	}
 
@@ -675,8 +786,52 @@ func (m *Md) makeTableTemplateData(t *schema.Table) map[string]interface{} {
 		"Constraints":      constraintsData,
 		"Indexes":          indexesData,
 		"Triggers":         triggersData,
+		"Relations":        relationsData,
 		"ReferencedTables": referencedTables,
+		"Lineage":          lineageData,
+	}
+}
+
+var cardinalityWords = map[schema.Cardinality]string{
+	schema.ZeroOrOne:  "Zero or one",
+	schema.ExactlyOne: "Exactly one",
+	schema.ZeroOrMore: "Zero or more",
+	schema.OneOrMore:  "One or more",
+}
+
+// hasLineageImage return true if the lineage graph image of the table is generated with the ER diagram of the table.
+func hasLineageImage(c *config.Config, t *schema.Table, er bool) bool {
+	return er && c.ER.Format != "d2" && t.HasLineage()
+}
+
+// usedByLinks return links to views that depend on the table.
+func (m *Md) usedByLinks() []string {
+	links := []string{}
+	for _, t := range m.usedBy {
+		links = append(links, fmt.Sprintf("[%s](%s%s.md)", t.Name, m.config.BaseUrl, t.Name))
+	}
+	return links
+}
+
+// lineageList return nested list items of upstream columns, following columns of upstream views.
+func (m *Md) lineageList(lineage []*schema.Lineage, depth int, visited map[string]struct{}) []string {
+	items := []string{}
+	indent := strings.Repeat("  ", depth)
+	for _, l := range lineage {
+		key := fmt.Sprintf("%s.%s", l.Table.Name, l.Column.Name)
+		if l.Table.External {
+			items = append(items, fmt.Sprintf("%s- %s", indent, key))
+			continue
+		}
+		items = append(items, fmt.Sprintf("%s- [%s](%s%s.md).%s", indent, l.Table.Name, m.config.BaseUrl, l.Table.Name, l.Column.Name))
+		if _, ok := visited[key]; ok {
+			continue
+		}
+		visited[key] = struct{}{}
+		items = append(items, m.lineageList(l.Column.Lineage, depth+1, visited)...)
+		delete(visited, key)
 	}
+	return items
 }
 
 func adjustTable(data [][]string) [][]string {
@@ -2,8 +2,12 @@ package output
 
 import (
 	"fmt"
+	"hash/fnv"
 	"io"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 	"text/template"
 
 	"github.com/tmdc-io/tbls/dict"
@@ -16,6 +20,201 @@ type Output interface {
 	OutputTable(wr io.Writer, s *schema.Table) error
 }
 
+// Parallel call fn with each index from 0 to n-1 using at most parallel goroutines ( default: number of CPUs ).
+// If fn return errors, Parallel return the error of the smallest index.
+func Parallel(n, parallel int, fn func(i int) error) error {
+	if parallel < 1 {
+		parallel = runtime.NumCPU()
+	}
+	errs := make([]error, n)
+	sem := make(chan struct{}, parallel)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Relation is relation data for ER diagram templates
+type Relation struct {
+	*schema.Relation
+	ColumnPairs []*ColumnPair
+}
+
+// ColumnPair is a pair of a child column and the parent column it references
+type ColumnPair struct {
+	Column       *schema.Column
+	ParentColumn *schema.Column
+}
+
+// NewRelations return relation data for ER diagram templates.
+// Relations are not modified because they are shared by tables rendered in parallel.
+func NewRelations(relations []*schema.Relation) []*Relation {
+	rs := []*Relation{}
+	for _, r := range relations {
+		rr := &Relation{
+			Relation: withDefaultCardinality(r),
+		}
+		for i, c := range r.Columns {
+			if i >= len(r.ParentColumns) {
+				break
+			}
+			rr.ColumnPairs = append(rr.ColumnPairs, &ColumnPair{
+				Column:       c,
+				ParentColumn: r.ParentColumns[i],
+			})
+		}
+		rs = append(rs, rr)
+	}
+	return rs
+}
+
+// withDefaultCardinality return the copy of the relation with detected cardinality if it is not set
+func withDefaultCardinality(r *schema.Relation) *schema.Relation {
+	if r.Cardinality != "" && r.ParentCardinality != "" {
+		return r
+	}
+	cr := *r
+	cr.SetDefaultCardinality()
+	return &cr
+}
+
+// Cluster is a group of tables drawn together in ER diagram
+type Cluster struct {
+	Name   string
+	Tables []*schema.Table
+}
+
+// GroupTables group tables into clusters by the first label ( `label` ), the schema part of the table name ( `schema` )
+// or the part of the table name before the first `_` ( `prefix` ).
+// Tables that do not belong to any group are not included.
+func GroupTables(tables []*schema.Table, groupBy string) ([]*Cluster, error) {
+	clusters := []*Cluster{}
+	if groupBy == "" {
+		return clusters, nil
+	}
+	var key func(t *schema.Table) string
+	switch groupBy {
+	case "label":
+		key = func(t *schema.Table) string {
+			if len(t.Labels) == 0 {
+				return ""
+			}
+			return t.Labels[0].Name
+		}
+	case "schema":
+		key = func(t *schema.Table) string {
+			i := strings.LastIndex(t.Name, ".")
+			if i < 0 {
+				return ""
+			}
+			return t.Name[:i]
+		}
+	case "prefix":
+		key = func(t *schema.Table) string {
+			n := t.Name[strings.LastIndex(t.Name, ".")+1:]
+			i := strings.Index(n, "_")
+			if i <= 0 {
+				return ""
+			}
+			return n[:i]
+		}
+	default:
+		return nil, fmt.Errorf("invalid groupBy '%s'", groupBy)
+	}
+	encountered := map[string]*Cluster{}
+	for _, t := range tables {
+		k := key(t)
+		if k == "" {
+			continue
+		}
+		c, ok := encountered[k]
+		if !ok {
+			c = &Cluster{Name: k}
+			encountered[k] = c
+			clusters = append(clusters, c)
+		}
+		c.Tables = append(c.Tables, t)
+	}
+	return clusters, nil
+}
+
+// GroupAllTables return clusters of GroupTables preceded by a cluster without name that has the tables not belonging to any group.
+func GroupAllTables(tables []*schema.Table, groupBy string) ([]*Cluster, error) {
+	clusters, err := GroupTables(tables, groupBy)
+	if err != nil {
+		return nil, err
+	}
+	grouped := map[*schema.Table]struct{}{}
+	for _, c := range clusters {
+		for _, t := range c.Tables {
+			grouped[t] = struct{}{}
+		}
+	}
+	rest := &Cluster{}
+	for _, t := range tables {
+		if _, ok := grouped[t]; !ok {
+			rest.Tables = append(rest.Tables, t)
+		}
+	}
+	if len(rest.Tables) == 0 {
+		return clusters, nil
+	}
+	return append([]*Cluster{rest}, clusters...), nil
+}
+
+// SeededTables return tables ordered by hash of the table name and seed.
+// The order of the other tables does not change when tables are added or removed.
+func SeededTables(tables []*schema.Table, seed int) []*schema.Table {
+	sorted := make([]*schema.Table, len(tables))
+	copy(sorted, tables)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return seededHash(seed, sorted[i].Name) < seededHash(seed, sorted[j].Name)
+	})
+	return sorted
+}
+
+// SeededRelations return relations ordered by hash of the relation and seed.
+func SeededRelations(relations []*schema.Relation, seed int) []*schema.Relation {
+	key := func(r *schema.Relation) string {
+		k := r.Table.Name
+		for _, c := range r.Columns {
+			k += ":" + c.Name
+		}
+		k += "->" + r.ParentTable.Name
+		for _, c := range r.ParentColumns {
+			k += ":" + c.Name
+		}
+		return k
+	}
+	sorted := make([]*schema.Relation, len(relations))
+	copy(sorted, relations)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return seededHash(seed, key(sorted[i])) < seededHash(seed, key(sorted[j]))
+	})
+	return sorted
+}
+
+func seededHash(seed int, v string) uint64 {
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%d:%s", seed, v)
+	return h.Sum64()
+}
+
 func Funcs(d *dict.Dict) map[string]interface{} {
 	return template.FuncMap{
 		"nl2br": func(text string) string {
@@ -38,6 +237,58 @@ func Funcs(d *dict.Dict) map[string]interface{} {
 			r := strings.NewReplacer("\r\n", "\\n", "\n", "\\n", "\r", "\\n")
 			return r.Replace(text)
 		},
+		"escape_dq": func(text string) string {
+			r := strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\r\n", "\\n", "\n", "\\n", "\r", "\\n")
+			return r.Replace(text)
+		},
+		"dot_arrow": func(c schema.Cardinality) string {
+			switch c {
+			case schema.ZeroOrOne:
+				return "teeodot"
+			case schema.ExactlyOne:
+				return "teetee"
+			case schema.OneOrMore:
+				return "crowtee"
+			default:
+				return "crowodot"
+			}
+		},
+		"puml_left": func(c schema.Cardinality) string {
+			switch c {
+			case schema.ZeroOrOne:
+				return "|o"
+			case schema.ExactlyOne:
+				return "||"
+			case schema.OneOrMore:
+				return "}|"
+			default:
+				return "}o"
+			}
+		},
+		"puml_right": func(c schema.Cardinality) string {
+			switch c {
+			case schema.ZeroOrOne:
+				return "o|"
+			case schema.ExactlyOne:
+				return "||"
+			case schema.OneOrMore:
+				return "|{"
+			default:
+				return "o{"
+			}
+		},
+		"d2_arrowhead": func(c schema.Cardinality) string {
+			switch c {
+			case schema.ZeroOrOne:
+				return "cf-one"
+			case schema.ExactlyOne:
+				return "cf-one-required"
+			case schema.OneOrMore:
+				return "cf-many-required"
+			default:
+				return "cf-many"
+			}
+		},
 		"lookup": func(text string) string {
 			return d.Lookup(text)
 		},
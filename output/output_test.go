@@ -1,6 +1,8 @@
 package output
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/tmdc-io/tbls/config"
@@ -51,3 +53,112 @@ func TestDistance(t *testing.T) {
 		t.Errorf("got %v\nwant %v", len(relations), want)
 	}
 }
+
+func TestParallel(t *testing.T) {
+	for _, parallel := range []int{0, 1, 4} {
+		got := make([]int, 10)
+		err := Parallel(len(got), parallel, func(i int) error {
+			got[i] = i * i
+			if i == 7 || i == 3 {
+				return fmt.Errorf("error %d", i)
+			}
+			return nil
+		})
+		if err == nil || err.Error() != "error 3" {
+			t.Errorf("parallel %d: got %v\nwant %v", parallel, err, "error 3")
+		}
+		for i, v := range got {
+			if v != i*i {
+				t.Errorf("parallel %d: got %v\nwant %v", parallel, v, i*i)
+			}
+		}
+	}
+}
+
+func TestGroupTables(t *testing.T) {
+	tables := []*schema.Table{
+		&schema.Table{Name: "public.users", Labels: schema.Labels{&schema.Label{Name: "user"}}},
+		&schema.Table{Name: "public.posts"},
+		&schema.Table{Name: "admin.users", Labels: schema.Labels{&schema.Label{Name: "user"}, &schema.Label{Name: "admin"}}},
+		&schema.Table{Name: "logs"},
+		&schema.Table{Name: "public.user_options"},
+	}
+	tests := []struct {
+		groupBy string
+		want    string
+	}{
+		{"", ""},
+		{"label", "user:public.users,admin.users"},
+		{"schema", "public:public.users,public.posts,public.user_options admin:admin.users"},
+		{"prefix", "user:public.user_options"},
+	}
+	for _, tt := range tests {
+		clusters, err := GroupTables(tables, tt.groupBy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, c := range clusters {
+			names := []string{}
+			for _, t := range c.Tables {
+				names = append(names, t.Name)
+			}
+			got = append(got, fmt.Sprintf("%s:%s", c.Name, strings.Join(names, ",")))
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("%s: got %v\nwant %v", tt.groupBy, strings.Join(got, " "), tt.want)
+		}
+	}
+	if _, err := GroupTables(tables, "unknown"); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestSeededTables(t *testing.T) {
+	names := func(tables []*schema.Table) string {
+		n := []string{}
+		for _, t := range tables {
+			n = append(n, t.Name)
+		}
+		return strings.Join(n, ",")
+	}
+	tables := []*schema.Table{}
+	for _, n := range []string{"a", "b", "c", "d", "e"} {
+		tables = append(tables, &schema.Table{Name: n})
+	}
+	got := SeededTables(tables, 1)
+	if names(got) == names(tables) {
+		t.Errorf("got %v\nwant shuffled", names(got))
+	}
+	if names(SeededTables(tables, 1)) != names(got) {
+		t.Error("got different order with the same seed")
+	}
+	// adding a table does not change the order of the other tables
+	added := SeededTables(append(tables, &schema.Table{Name: "f"}), 1)
+	withoutF := []*schema.Table{}
+	for _, t := range added {
+		if t.Name != "f" {
+			withoutF = append(withoutF, t)
+		}
+	}
+	if names(withoutF) != names(got) {
+		t.Errorf("got %v\nwant %v", names(withoutF), names(got))
+	}
+}
+
+func TestNewRelationsDoNotModifyRelations(t *testing.T) {
+	s, err := datasource.Analyze(config.DSN{URL: "json://../testdata/testdb.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := s.Relations[0]
+	r.Cardinality = ""
+	r.ParentCardinality = ""
+	rs := NewRelations([]*schema.Relation{r})
+	if rs[0].Cardinality == "" || rs[0].ParentCardinality == "" {
+		t.Errorf("got %v : %v\nwant detected cardinality", rs[0].Cardinality, rs[0].ParentCardinality)
+	}
+	if r.Cardinality != "" || r.ParentCardinality != "" {
+		t.Errorf("got %v : %v\nwant not modified", r.Cardinality, r.ParentCardinality)
+	}
+}
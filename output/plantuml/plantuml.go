@@ -62,6 +62,8 @@ func (p *PlantUML) tableTemplate() (string, error) {
 
 // OutputSchema output dot format for full relation.
 func (p *PlantUML) OutputSchema(wr io.Writer, s *schema.Schema) error {
+	// detect cardinality before column names are prefixed
+	relations := output.NewRelations(s.Relations)
 	for _, t := range s.Tables {
 		err := addPrefix(t)
 		if err != nil {
@@ -69,6 +71,11 @@ func (p *PlantUML) OutputSchema(wr io.Writer, s *schema.Schema) error {
 		}
 	}
 
+	groups, err := output.GroupAllTables(s.Tables, p.config.ER.GroupBy)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	ts, err := p.schemaTemplate()
 	if err != nil {
 		return errors.WithStack(err)
@@ -76,6 +83,8 @@ func (p *PlantUML) OutputSchema(wr io.Writer, s *schema.Schema) error {
 	tmpl := template.Must(template.New(s.Name).Funcs(output.Funcs(&p.config.MergedDict)).Parse(ts))
 	err = tmpl.Execute(wr, map[string]interface{}{
 		"Schema":      s,
+		"Groups":      groups,
+		"Relations":   relations,
 		"showComment": p.config.ER.Comment,
 	})
 	if err != nil {
@@ -87,16 +96,21 @@ func (p *PlantUML) OutputSchema(wr io.Writer, s *schema.Schema) error {
 
 // OutputTable output dot format for table.
 func (p *PlantUML) OutputTable(wr io.Writer, t *schema.Table) error {
-	tables, relations, err := t.CollectTablesAndRelations(*p.config.ER.Distance, true)
-
+	direction, err := schema.ParseDirection(p.config.ER.Direction)
 	if err != nil {
 		return errors.WithStack(err)
 	}
+	tables, relations := t.CollectRelatedTables(*p.config.ER.Distance, direction)
+	rs := output.NewRelations(relations)
 	for _, t := range tables {
 		if err := addPrefix(t); err != nil {
 			return errors.WithStack(err)
 		}
 	}
+	groups, err := output.GroupAllTables(tables, p.config.ER.GroupBy)
+	if err != nil {
+		return errors.WithStack(err)
+	}
 	ts, err := p.tableTemplate()
 	if err != nil {
 		return errors.WithStack(err)
@@ -105,7 +119,8 @@ func (p *PlantUML) OutputTable(wr io.Writer, t *schema.Table) error {
 	err = tmpl.Execute(wr, map[string]interface{}{
 		"Table":       tables[0],
 		"Tables":      tables[1:],
-		"Relations":   relations,
+		"Groups":      groups,
+		"Relations":   rs,
 		"showComment": p.config.ER.Comment,
 	})
 	if err != nil {
@@ -37,6 +37,35 @@ func TestOutputSchema(t *testing.T) {
 	}
 }
 
+func TestOutputSchemaGroupBy(t *testing.T) {
+	s := newTestSchema()
+	c, err := config.New()
+	if err != nil {
+		t.Error(err)
+	}
+	err = c.LoadConfigFile(filepath.Join(testdataDir(), "out_test_tbls.yml"))
+	if err != nil {
+		t.Error(err)
+	}
+	err = c.MergeAdditionalData(s)
+	if err != nil {
+		t.Error(err)
+	}
+	c.ER.GroupBy = "label"
+	s.Tables[0].Labels = schema.Labels{&schema.Label{Name: "blog"}}
+	o := New(c)
+	buf := &bytes.Buffer{}
+	err = o.OutputSchema(buf, s)
+	if err != nil {
+		t.Error(err)
+	}
+	want, _ := os.ReadFile(filepath.Join(testdataDir(), "plantuml_test_schema_group_by.puml.golden"))
+	got := buf.String()
+	if got != string(want) {
+		t.Errorf("got %v\nwant %v", got, string(want))
+	}
+}
+
 func TestOutputSchemaTemplate(t *testing.T) {
 	s := newTestSchema()
 	c, err := config.New()
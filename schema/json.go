@@ -99,6 +99,7 @@ func (c Column) MarshalJSON() ([]byte, error) {
 			Nullable        bool        `json:"nullable"`
 			Default         string      `json:"default"`
 			ExtraDef        string      `json:"extra_def,omitempty"`
+			Lineage         []*Lineage  `json:"lineage,omitempty"`
 			Comment         string      `json:"comment"`
 			ParentRelations []*Relation `json:"-"`
 			ChildRelations  []*Relation `json:"-"`
@@ -109,6 +110,7 @@ func (c Column) MarshalJSON() ([]byte, error) {
 			Default:         c.Default.String,
 			Comment:         c.Comment,
 			ExtraDef:        c.ExtraDef,
+			Lineage:         c.Lineage,
 			ParentRelations: c.ParentRelations,
 			ChildRelations:  c.ChildRelations,
 		})
@@ -120,6 +122,7 @@ func (c Column) MarshalJSON() ([]byte, error) {
 		Default         *string     `json:"default"`
 		Comment         string      `json:"comment"`
 		ExtraDef        string      `json:"extra_def,omitempty"`
+		Lineage         []*Lineage  `json:"lineage,omitempty"`
 		ParentRelations []*Relation `json:"-"`
 		ChildRelations  []*Relation `json:"-"`
 	}{
@@ -129,6 +132,7 @@ func (c Column) MarshalJSON() ([]byte, error) {
 		Default:         nil,
 		Comment:         c.Comment,
 		ExtraDef:        c.ExtraDef,
+		Lineage:         c.Lineage,
 		ParentRelations: c.ParentRelations,
 		ChildRelations:  c.ChildRelations,
 	})
@@ -146,19 +150,23 @@ func (r Relation) MarshalJSON() ([]byte, error) {
 	}
 
 	return json.Marshal(&struct {
-		Table         string   `json:"table"`
-		Columns       []string `json:"columns"`
-		ParentTable   string   `json:"parent_table"`
-		ParentColumns []string `json:"parent_columns"`
-		Def           string   `json:"def"`
-		Virtual       bool     `json:"virtual"`
+		Table             string      `json:"table"`
+		Columns           []string    `json:"columns"`
+		ParentTable       string      `json:"parent_table"`
+		ParentColumns     []string    `json:"parent_columns"`
+		Def               string      `json:"def"`
+		Virtual           bool        `json:"virtual"`
+		Cardinality       Cardinality `json:"cardinality,omitempty"`
+		ParentCardinality Cardinality `json:"parent_cardinality,omitempty"`
 	}{
-		Table:         r.Table.Name,
-		Columns:       columns,
-		ParentTable:   r.ParentTable.Name,
-		ParentColumns: parentColumns,
-		Def:           r.Def,
-		Virtual:       r.Virtual,
+		Table:             r.Table.Name,
+		Columns:           columns,
+		ParentTable:       r.ParentTable.Name,
+		ParentColumns:     parentColumns,
+		Def:               r.Def,
+		Virtual:           r.Virtual,
+		Cardinality:       r.Cardinality,
+		ParentCardinality: r.ParentCardinality,
 	})
 }
 
@@ -206,6 +214,7 @@ func (c *Column) UnmarshalJSON(data []byte) error {
 		Default         *string     `json:"default"`
 		Comment         string      `json:"comment"`
 		ExtraDef        string      `json:"extra_def,omitempty"`
+		Lineage         []*Lineage  `json:"lineage,omitempty"`
 		ParentRelations []*Relation `json:"-"`
 		ChildRelations  []*Relation `json:"-"`
 	}{}
@@ -224,6 +233,7 @@ func (c *Column) UnmarshalJSON(data []byte) error {
 		c.Default.String = ""
 	}
 	c.ExtraDef = s.ExtraDef
+	c.Lineage = s.Lineage
 	c.Comment = s.Comment
 	return nil
 }
@@ -231,12 +241,14 @@ func (c *Column) UnmarshalJSON(data []byte) error {
 // UnmarshalJSON unmarshal JSON to schema.Relation
 func (r *Relation) UnmarshalJSON(data []byte) error {
 	s := struct {
-		Table         string   `json:"table"`
-		Columns       []string `json:"columns"`
-		ParentTable   string   `json:"parent_table"`
-		ParentColumns []string `json:"parent_columns"`
-		Def           string   `json:"def"`
-		Virtual       bool     `json:"virtual"`
+		Table             string      `json:"table"`
+		Columns           []string    `json:"columns"`
+		ParentTable       string      `json:"parent_table"`
+		ParentColumns     []string    `json:"parent_columns"`
+		Def               string      `json:"def"`
+		Virtual           bool        `json:"virtual"`
+		Cardinality       Cardinality `json:"cardinality,omitempty"`
+		ParentCardinality Cardinality `json:"parent_cardinality,omitempty"`
 	}{}
 	err := json.Unmarshal(data, &s)
 	if err != nil {
@@ -262,5 +274,37 @@ func (r *Relation) UnmarshalJSON(data []byte) error {
 	}
 	r.Def = s.Def
 	r.Virtual = s.Virtual
+	r.Cardinality = s.Cardinality
+	r.ParentCardinality = s.ParentCardinality
+	return nil
+}
+
+// MarshalJSON return custom JSON byte
+func (l Lineage) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		Table  string `json:"table"`
+		Column string `json:"column"`
+	}{
+		Table:  l.Table.Name,
+		Column: l.Column.Name,
+	})
+}
+
+// UnmarshalJSON unmarshal JSON to schema.Lineage
+func (l *Lineage) UnmarshalJSON(data []byte) error {
+	s := struct {
+		Table  string `json:"table"`
+		Column string `json:"column"`
+	}{}
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+	l.Table = &Table{
+		Name: s.Table,
+	}
+	l.Column = &Column{
+		Name: s.Column,
+	}
 	return nil
 }
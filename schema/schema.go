@@ -66,10 +66,17 @@ type Column struct {
 	Default         sql.NullString `json:"default"`
 	Comment         string         `json:"comment"`
 	ExtraDef        string         `json:"extra_def,omitempty" yaml:"extraDef,omitempty"`
+	Lineage         []*Lineage     `json:"lineage,omitempty" yaml:"lineage,omitempty"`
 	ParentRelations []*Relation    `json:"-"`
 	ChildRelations  []*Relation    `json:"-"`
 }
 
+// Lineage is the struct for an upstream column that feeds a view column
+type Lineage struct {
+	Table  *Table  `json:"table"`
+	Column *Column `json:"column"`
+}
+
 // Table is the struct for database table
 type Table struct {
 	Name             string        `json:"name"`
@@ -87,14 +94,26 @@ type Table struct {
 
 // Relation is the struct for table relation
 type Relation struct {
-	Table         *Table    `json:"table"`
-	Columns       []*Column `json:"columns"`
-	ParentTable   *Table    `json:"parent_table" yaml:"parentTable"`
-	ParentColumns []*Column `json:"parent_columns" yaml:"parentColumns"`
-	Def           string    `json:"def"`
-	Virtual       bool      `json:"virtual"`
+	Table             *Table      `json:"table"`
+	Columns           []*Column   `json:"columns"`
+	ParentTable       *Table      `json:"parent_table" yaml:"parentTable"`
+	ParentColumns     []*Column   `json:"parent_columns" yaml:"parentColumns"`
+	Def               string      `json:"def"`
+	Virtual           bool        `json:"virtual"`
+	Cardinality       Cardinality `json:"cardinality,omitempty"`
+	ParentCardinality Cardinality `json:"parent_cardinality,omitempty" yaml:"parentCardinality,omitempty"`
 }
 
+// Cardinality is the number of rows on one side of a relation
+type Cardinality string
+
+const (
+	ZeroOrOne  Cardinality = "zero_or_one"
+	ExactlyOne Cardinality = "exactly_one"
+	ZeroOrMore Cardinality = "zero_or_more"
+	OneOrMore  Cardinality = "one_or_more"
+)
+
 type DriverMeta struct {
 	CurrentSchema string     `json:"current_schema,omitempty" yaml:"currentSchema,omitempty"`
 	SearchPaths   []string   `json:"search_paths,omitempty" yaml:"searchPaths,omitempty"`
@@ -229,6 +248,20 @@ func (t *Table) FindConstrainsByColumnName(name string) []*Constraint {
 	return cts
 }
 
+// ReferencedBy return tables ( views ) that reference the table
+func (s *Schema) ReferencedBy(t *Table) []*Table {
+	tables := []*Table{}
+	for _, tt := range s.Tables {
+		for _, rt := range tt.ReferencedTables {
+			if rt.Name == t.Name {
+				tables = append(tables, tt)
+				break
+			}
+		}
+	}
+	return tables
+}
+
 func (t *Table) HasColumnWithExtraDef() bool {
 	for _, c := range t.Columns {
 		if c.ExtraDef != "" {
@@ -238,6 +271,16 @@ func (t *Table) HasColumnWithExtraDef() bool {
 	return false
 }
 
+// HasLineage return true if table has columns with lineage
+func (t *Table) HasLineage() bool {
+	for _, c := range t.Columns {
+		if len(c.Lineage) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // Sort schema tables, columns, relations, and constrains
 func (s *Schema) Sort() error {
 	for _, t := range s.Tables {
@@ -271,6 +314,83 @@ func (s *Schema) Sort() error {
 	return nil
 }
 
+// ParseCardinality parse cardinality name ( or notation like `0..1`, `1`, `0..*` and `1..*` ).
+func ParseCardinality(v string) (Cardinality, error) {
+	switch strings.ToLower(strings.ReplaceAll(strings.TrimSpace(v), " ", "_")) {
+	case string(ZeroOrOne), "0..1":
+		return ZeroOrOne, nil
+	case string(ExactlyOne), "1", "1..1":
+		return ExactlyOne, nil
+	case string(ZeroOrMore), "0..*", "*":
+		return ZeroOrMore, nil
+	case string(OneOrMore), "1..*":
+		return OneOrMore, nil
+	}
+	return "", errors.Errorf("invalid cardinality '%s'", v)
+}
+
+// DetectCardinality detect cardinality of the child side and the parent side of relation
+// from nullability and unique constraints of the child columns.
+func (r *Relation) DetectCardinality() (Cardinality, Cardinality) {
+	child := ZeroOrMore
+	if r.Table.hasUniqueColumns(r.Columns) {
+		child = ZeroOrOne
+	}
+	parent := ExactlyOne
+	for _, c := range r.Columns {
+		if c.Nullable {
+			parent = ZeroOrOne
+			break
+		}
+	}
+	return child, parent
+}
+
+// SetDefaultCardinality set detected cardinality to the side of relation that is not set.
+func (r *Relation) SetDefaultCardinality() {
+	child, parent := r.DetectCardinality()
+	if r.Cardinality == "" {
+		r.Cardinality = child
+	}
+	if r.ParentCardinality == "" {
+		r.ParentCardinality = parent
+	}
+}
+
+// hasUniqueColumns return true if the combination of columns is unique by a primary key or an unique constraint/index.
+func (t *Table) hasUniqueColumns(columns []*Column) bool {
+	names := map[string]struct{}{}
+	for _, c := range columns {
+		names[c.Name] = struct{}{}
+	}
+	covered := func(cols []string) bool {
+		if len(cols) == 0 {
+			return false
+		}
+		for _, c := range cols {
+			if _, ok := names[c]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+	for _, c := range t.Constraints {
+		switch strings.ToUpper(c.Type) {
+		case "PRIMARY KEY", "P", "UNIQUE", "U", "UNIQUE KEY":
+			if covered(c.Columns) {
+				return true
+			}
+		}
+	}
+	for _, i := range t.Indexes {
+		def := strings.ToUpper(i.Def)
+		if (strings.Contains(def, "UNIQUE") || strings.Contains(def, "PRIMARY")) && covered(i.Columns) {
+			return true
+		}
+	}
+	return false
+}
+
 // Repair column relations
 func (s *Schema) Repair() error {
 	for _, t := range s.Tables {
@@ -294,6 +414,19 @@ func (s *Schema) Repair() error {
 			}
 			t.ReferencedTables[i] = tt
 		}
+		for _, c := range t.Columns {
+			for _, l := range c.Lineage {
+				lt, err := s.FindTableByName(l.Table.Name)
+				if err != nil {
+					l.Table.External = true
+					continue
+				}
+				l.Table = lt
+				if lc, err := lt.FindColumnByName(l.Column.Name); err == nil {
+					l.Column = lc
+				}
+			}
+		}
 	}
 
 	for _, r := range s.Relations {
@@ -328,59 +461,75 @@ func (s *Schema) Repair() error {
 	return nil
 }
 
+// Direction is the direction of relations to follow when collecting related tables
+type Direction string
+
+const (
+	DirectionBoth     Direction = "both"
+	DirectionParents  Direction = "parents"
+	DirectionChildren Direction = "children"
+)
+
+// ParseDirection parse direction name. Empty string is DirectionBoth.
+func ParseDirection(v string) (Direction, error) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "", string(DirectionBoth):
+		return DirectionBoth, nil
+	case string(DirectionParents), "parent":
+		return DirectionParents, nil
+	case string(DirectionChildren), "child":
+		return DirectionChildren, nil
+	}
+	return "", errors.Errorf("invalid direction '%s'", v)
+}
+
+// CollectTablesAndRelations collect tables and relations within distance from the table, following both parents and children.
+// root is no longer used and kept for compatibility.
 func (t *Table) CollectTablesAndRelations(distance int, root bool) ([]*Table, []*Relation, error) {
-	tables := []*Table{}
+	tables, relations := t.CollectRelatedTables(distance, DirectionBoth)
+	return tables, relations, nil
+}
+
+// CollectRelatedTables collect tables and relations within distance from the table by breadth-first search.
+// Tables are ordered by the shortest distance from the table.
+func (t *Table) CollectRelatedTables(distance int, direction Direction) ([]*Table, []*Relation) {
+	tables := []*Table{t}
 	relations := []*Relation{}
-	tables = append(tables, t)
-	if distance == 0 {
-		return tables, relations, nil
-	}
-	distance = distance - 1
-	for _, c := range t.Columns {
-		for _, r := range c.ParentRelations {
-			relations = append(relations, r)
-			ts, rs, err := r.ParentTable.CollectTablesAndRelations(distance, false)
-			if err != nil {
-				return nil, nil, err
-			}
-			tables = append(tables, ts...)
-			relations = append(relations, rs...)
+	distances := map[*Table]int{t: 0}
+	encounteredR := map[*Relation]struct{}{}
+
+	follow := func(r *Relation, next *Table, d int) {
+		if _, ok := encounteredR[r]; ok {
+			return
 		}
-		for _, r := range c.ChildRelations {
-			relations = append(relations, r)
-			ts, rs, err := r.Table.CollectTablesAndRelations(distance, false)
-			if err != nil {
-				return nil, nil, err
-			}
-			tables = append(tables, ts...)
-			relations = append(relations, rs...)
+		encounteredR[r] = struct{}{}
+		relations = append(relations, r)
+		if _, ok := distances[next]; ok {
+			return
 		}
+		distances[next] = d
+		tables = append(tables, next)
 	}
 
-	if !root {
-		return tables, relations, nil
-	}
-
-	uTables := []*Table{}
-	encounteredT := make(map[string]bool)
-	for _, t := range tables {
-		if !encounteredT[t.Name] {
-			encounteredT[t.Name] = true
-			uTables = append(uTables, t)
+	for i := 0; i < len(tables); i++ {
+		current := tables[i]
+		d := distances[current]
+		if d >= distance {
+			continue
 		}
-	}
-
-	uRelations := []*Relation{}
-	encounteredR := make(map[*Relation]bool)
-	for _, r := range relations {
-		if !encounteredR[r] {
-			encounteredR[r] = true
-			if !encounteredT[r.ParentTable.Name] || !encounteredT[r.Table.Name] {
-				continue
+		for _, c := range current.Columns {
+			if direction != DirectionChildren {
+				for _, r := range c.ParentRelations {
+					follow(r, r.ParentTable, d+1)
+				}
+			}
+			if direction != DirectionParents {
+				for _, r := range c.ChildRelations {
+					follow(r, r.Table, d+1)
+				}
 			}
-			uRelations = append(uRelations, r)
 		}
 	}
 
-	return uTables, uRelations, nil
+	return tables, relations
 }
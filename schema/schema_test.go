@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -170,6 +171,154 @@ func TestRepair(t *testing.T) {
 	}
 }
 
+func TestRepairLineage(t *testing.T) {
+	in := `{
+  "name": "testschema",
+  "tables": [
+    {"name": "a", "type": "BASE TABLE", "columns": [{"name": "a", "type": "int", "nullable": false}]},
+    {"name": "v", "type": "VIEW", "columns": [
+      {"name": "x", "type": "int", "nullable": true, "lineage": [{"table": "a", "column": "a"}, {"table": "ext", "column": "e"}]}
+    ]}
+  ],
+  "relations": []
+}`
+	s := &Schema{}
+	if err := json.Unmarshal([]byte(in), s); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Repair(); err != nil {
+		t.Fatal(err)
+	}
+	a, _ := s.FindTableByName("a")
+	v, _ := s.FindTableByName("v")
+	l := v.Columns[0].Lineage
+	if len(l) != 2 {
+		t.Fatalf("got %v\nwant %v", len(l), 2)
+	}
+	if l[0].Table != a || l[0].Column != a.Columns[0] {
+		t.Errorf("lineage is not repaired: %#v", l[0])
+	}
+	if !l[1].Table.External || l[1].Column.Name != "e" {
+		t.Errorf("got %#v\nwant external table", l[1].Table)
+	}
+
+	b, err := json.Marshal(v.Columns[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"name":"x","type":"int","nullable":true,"default":null,"comment":"","lineage":[{"table":"a","column":"a"},{"table":"ext","column":"e"}]}`
+	if got := string(b); got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func TestDetectCardinality(t *testing.T) {
+	tests := []struct {
+		nullable   bool
+		constraint *Constraint
+		index      *Index
+		wantChild  Cardinality
+		wantParent Cardinality
+	}{
+		{false, nil, nil, ZeroOrMore, ExactlyOne},
+		{true, nil, nil, ZeroOrMore, ZeroOrOne},
+		{false, &Constraint{Name: "u", Type: "UNIQUE", Columns: []string{"a", "b"}}, nil, ZeroOrOne, ExactlyOne},
+		{false, &Constraint{Name: "u", Type: "UNIQUE", Columns: []string{"a", "c"}}, nil, ZeroOrMore, ExactlyOne},
+		{true, nil, &Index{Name: "i", Def: "CREATE UNIQUE INDEX i ON t (a)", Columns: []string{"a"}}, ZeroOrOne, ZeroOrOne},
+		{false, nil, &Index{Name: "i", Def: "CREATE INDEX i ON t (a, b)", Columns: []string{"a", "b"}}, ZeroOrMore, ExactlyOne},
+	}
+	for i, tt := range tests {
+		ca := &Column{Name: "a"}
+		cb := &Column{Name: "b", Nullable: tt.nullable}
+		ta := &Table{Name: "t", Columns: []*Column{ca, cb, &Column{Name: "c"}}}
+		if tt.constraint != nil {
+			ta.Constraints = []*Constraint{tt.constraint}
+		}
+		if tt.index != nil {
+			ta.Indexes = []*Index{tt.index}
+		}
+		r := &Relation{Table: ta, Columns: []*Column{ca, cb}}
+		gotChild, gotParent := r.DetectCardinality()
+		if gotChild != tt.wantChild {
+			t.Errorf("[%d] got %v\nwant %v", i, gotChild, tt.wantChild)
+		}
+		if gotParent != tt.wantParent {
+			t.Errorf("[%d] got %v\nwant %v", i, gotParent, tt.wantParent)
+		}
+	}
+}
+
+func TestParseCardinality(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Cardinality
+		wantErr bool
+	}{
+		{"zero_or_one", ZeroOrOne, false},
+		{"Exactly One", ExactlyOne, false},
+		{"0..*", ZeroOrMore, false},
+		{"1..*", OneOrMore, false},
+		{"1", ExactlyOne, false},
+		{"many", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCardinality(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: got error %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %v\nwant %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCollectRelatedTables(t *testing.T) {
+	// a -> b -> c -> a, d -> b
+	tables := map[string]*Table{}
+	for _, n := range []string{"a", "b", "c", "d"} {
+		tables[n] = &Table{Name: n, Columns: []*Column{&Column{Name: "id"}, &Column{Name: "parent_id"}}}
+	}
+	relate := func(child, parent string) {
+		r := &Relation{
+			Table:         tables[child],
+			Columns:       []*Column{tables[child].Columns[1]},
+			ParentTable:   tables[parent],
+			ParentColumns: []*Column{tables[parent].Columns[0]},
+		}
+		tables[child].Columns[1].ParentRelations = append(tables[child].Columns[1].ParentRelations, r)
+		tables[parent].Columns[0].ChildRelations = append(tables[parent].Columns[0].ChildRelations, r)
+	}
+	relate("a", "b")
+	relate("b", "c")
+	relate("c", "a")
+	relate("d", "b")
+
+	tests := []struct {
+		distance      int
+		direction     Direction
+		wantTables    []string
+		wantRelations int
+	}{
+		{0, DirectionBoth, []string{"a"}, 0},
+		{1, DirectionBoth, []string{"a", "c", "b"}, 2},
+		{2, DirectionBoth, []string{"a", "c", "b", "d"}, 4},
+		{2, DirectionParents, []string{"a", "b", "c"}, 2},
+		{2, DirectionChildren, []string{"a", "c", "b"}, 2},
+		{3, DirectionChildren, []string{"a", "c", "b", "d"}, 4},
+	}
+	for _, tt := range tests {
+		gotTables, gotRelations := tables["a"].CollectRelatedTables(tt.distance, tt.direction)
+		names := []string{}
+		for _, gt := range gotTables {
+			names = append(names, gt.Name)
+		}
+		compareStrings(t, strings.Join(names, ","), strings.Join(tt.wantTables, ","))
+		if len(gotRelations) != tt.wantRelations {
+			t.Errorf("distance %d %s: got %v\nwant %v", tt.distance, tt.direction, len(gotRelations), tt.wantRelations)
+		}
+	}
+}
+
 func compareStrings(tb testing.TB, got, want string) {
 	tb.Helper()
 	if got != want {
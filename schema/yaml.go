@@ -58,6 +58,7 @@ func (c Column) MarshalYAML() ([]byte, error) {
 			Nullable        bool        `yaml:"nullable"`
 			Default         string      `yaml:"default"`
 			ExtraDef        string      `yaml:"extraDef,omitempty"`
+			Lineage         []*Lineage  `yaml:"lineage,omitempty"`
 			Comment         string      `yaml:"comment"`
 			ParentRelations []*Relation `yaml:"-"`
 			ChildRelations  []*Relation `yaml:"-"`
@@ -68,6 +69,7 @@ func (c Column) MarshalYAML() ([]byte, error) {
 			Default:         c.Default.String,
 			Comment:         c.Comment,
 			ExtraDef:        c.ExtraDef,
+			Lineage:         c.Lineage,
 			ParentRelations: c.ParentRelations,
 			ChildRelations:  c.ChildRelations,
 		})
@@ -78,6 +80,7 @@ func (c Column) MarshalYAML() ([]byte, error) {
 		Nullable        bool        `yaml:"nullable"`
 		Default         *string     `yaml:"default"`
 		ExtraDef        string      `yaml:"extraDef,omitempty"`
+		Lineage         []*Lineage  `yaml:"lineage,omitempty"`
 		Comment         string      `yaml:"comment"`
 		ParentRelations []*Relation `yaml:"-"`
 		ChildRelations  []*Relation `yaml:"-"`
@@ -87,6 +90,7 @@ func (c Column) MarshalYAML() ([]byte, error) {
 		Nullable:        c.Nullable,
 		Default:         nil,
 		ExtraDef:        c.ExtraDef,
+		Lineage:         c.Lineage,
 		Comment:         c.Comment,
 		ParentRelations: c.ParentRelations,
 		ChildRelations:  c.ChildRelations,
@@ -105,19 +109,23 @@ func (r Relation) MarshalYAML() ([]byte, error) {
 	}
 
 	return yaml.Marshal(&struct {
-		Table         string   `yaml:"table"`
-		Columns       []string `yaml:"columns"`
-		ParentTable   string   `yaml:"parentTable"`
-		ParentColumns []string `yaml:"parentColumns"`
-		Def           string   `yaml:"def"`
-		Virtual       bool     `yaml:"virtual"`
+		Table             string      `yaml:"table"`
+		Columns           []string    `yaml:"columns"`
+		ParentTable       string      `yaml:"parentTable"`
+		ParentColumns     []string    `yaml:"parentColumns"`
+		Def               string      `yaml:"def"`
+		Virtual           bool        `yaml:"virtual"`
+		Cardinality       Cardinality `yaml:"cardinality,omitempty"`
+		ParentCardinality Cardinality `yaml:"parentCardinality,omitempty"`
 	}{
-		Table:         r.Table.Name,
-		Columns:       columns,
-		ParentTable:   r.ParentTable.Name,
-		ParentColumns: parentColumns,
-		Def:           r.Def,
-		Virtual:       r.Virtual,
+		Table:             r.Table.Name,
+		Columns:           columns,
+		ParentTable:       r.ParentTable.Name,
+		ParentColumns:     parentColumns,
+		Def:               r.Def,
+		Virtual:           r.Virtual,
+		Cardinality:       r.Cardinality,
+		ParentCardinality: r.ParentCardinality,
 	})
 }
 
@@ -165,6 +173,7 @@ func (c *Column) UnmarshalYAML(data []byte) error {
 		Default         *string     `yaml:"default"`
 		Comment         string      `yaml:"comment"`
 		ExtraDef        string      `yaml:"extraDef,omitempty"`
+		Lineage         []*Lineage  `yaml:"lineage,omitempty"`
 		ParentRelations []*Relation `yaml:"-"`
 		ChildRelations  []*Relation `yaml:"-"`
 	}{}
@@ -184,6 +193,7 @@ func (c *Column) UnmarshalYAML(data []byte) error {
 		c.Default.String = ""
 	}
 	c.ExtraDef = s.ExtraDef
+	c.Lineage = s.Lineage
 	c.Comment = s.Comment
 	return nil
 }
@@ -191,12 +201,14 @@ func (c *Column) UnmarshalYAML(data []byte) error {
 // UnmarshalYAML unmarshal YAML to schema.Column
 func (r *Relation) UnmarshalYAML(data []byte) error {
 	s := struct {
-		Table         string   `yaml:"table"`
-		Columns       []string `yaml:"columns"`
-		ParentTable   string   `yaml:"parentTable"`
-		ParentColumns []string `yaml:"parentColumns"`
-		Def           string   `yaml:"def"`
-		Virtual       bool     `yaml:"virtual"`
+		Table             string      `yaml:"table"`
+		Columns           []string    `yaml:"columns"`
+		ParentTable       string      `yaml:"parentTable"`
+		ParentColumns     []string    `yaml:"parentColumns"`
+		Def               string      `yaml:"def"`
+		Virtual           bool        `yaml:"virtual"`
+		Cardinality       Cardinality `yaml:"cardinality,omitempty"`
+		ParentCardinality Cardinality `yaml:"parentCardinality,omitempty"`
 	}{}
 	err := yaml.Unmarshal(data, &s)
 	if err != nil {
@@ -222,5 +234,37 @@ func (r *Relation) UnmarshalYAML(data []byte) error {
 	}
 	r.Def = s.Def
 	r.Virtual = s.Virtual
+	r.Cardinality = s.Cardinality
+	r.ParentCardinality = s.ParentCardinality
+	return nil
+}
+
+// MarshalYAML return custom YAML byte
+func (l Lineage) MarshalYAML() ([]byte, error) {
+	return yaml.Marshal(&struct {
+		Table  string `yaml:"table"`
+		Column string `yaml:"column"`
+	}{
+		Table:  l.Table.Name,
+		Column: l.Column.Name,
+	})
+}
+
+// UnmarshalYAML unmarshal YAML to schema.Lineage
+func (l *Lineage) UnmarshalYAML(data []byte) error {
+	s := struct {
+		Table  string `yaml:"table"`
+		Column string `yaml:"column"`
+	}{}
+	err := yaml.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+	l.Table = &Table{
+		Name: s.Table,
+	}
+	l.Column = &Column{
+		Name: s.Column,
+	}
 	return nil
 }
@@ -0,0 +1,259 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gobuffalo/packr/v2"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/output"
+	"github.com/tmdc-io/tbls/output/gviz"
+	"github.com/tmdc-io/tbls/output/md"
+	"github.com/tmdc-io/tbls/schema"
+)
+
+// Loader load config and analyze schema
+type Loader func() (*config.Config, *schema.Schema, error)
+
+// Server serves HTML documents and ER diagrams of schema
+type Server struct {
+	load    Loader
+	config  *config.Config
+	schema  *schema.Schema
+	version int64
+	mu      sync.RWMutex
+	box     *packr.Box
+}
+
+// New return Server
+func New(load Loader) *Server {
+	return &Server{
+		load: load,
+		box:  packr.New("server", "./templates"),
+	}
+}
+
+// Reload load config and re-analyze schema.
+func (s *Server) Reload() error {
+	c, sc, err := s.load()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	// documents link each other and diagrams are rendered on request
+	c.BaseUrl = ""
+	c.ER.Format = "svg"
+	c.ER.Link = true
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config = c
+	s.schema = sc
+	s.version = time.Now().UnixNano()
+	return nil
+}
+
+// Watch reload when the config file is changed or interval has passed. interval 0 disables periodic reloading.
+func (s *Server) Watch(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	modTime := s.configModTime()
+	last := time.Now()
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			mt := s.configModTime()
+			if !mt.Equal(modTime) || (interval > 0 && now.Sub(last) >= interval) {
+				modTime = mt
+				last = now
+				if err := s.Reload(); err != nil {
+					log.Errorf("failed to reload: %s", err)
+					continue
+				}
+				log.Info("Reloaded")
+			}
+		}
+	}
+}
+
+func (s *Server) configModTime() time.Time {
+	s.mu.RLock()
+	path := s.config.Path
+	s.mu.RUnlock()
+	if path == "" {
+		return time.Time{}
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return fi.ModTime()
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	c := s.config
+	sc := s.schema
+	version := s.version
+	s.mu.RUnlock()
+
+	p := strings.TrimPrefix(r.URL.Path, "/")
+	var err error
+	switch {
+	case p == "_version":
+		_, err = fmt.Fprintf(w, "%d", version)
+	case p == "" || p == "index.html":
+		err = s.outputSchema(w, c, sc, version)
+	case p == "schema.svg":
+		err = s.outputImage(w, c, func(g *gviz.Gviz, buf *bytes.Buffer) error {
+			return g.OutputSchema(buf, sc)
+		})
+	case strings.HasSuffix(p, ".lineage.svg"):
+		t, ferr := sc.FindTableByName(strings.TrimSuffix(p, ".lineage.svg"))
+		if ferr != nil {
+			http.NotFound(w, r)
+			return
+		}
+		err = s.outputImage(w, c, func(g *gviz.Gviz, buf *bytes.Buffer) error {
+			return g.OutputLineage(buf, t)
+		})
+	case strings.HasSuffix(p, ".svg"):
+		t, ferr := sc.FindTableByName(strings.TrimSuffix(p, ".svg"))
+		if ferr != nil {
+			http.NotFound(w, r)
+			return
+		}
+		err = s.outputImage(w, c, func(g *gviz.Gviz, buf *bytes.Buffer) error {
+			return g.OutputTable(buf, t)
+		})
+	case strings.HasSuffix(p, ".html") || strings.HasSuffix(p, ".md"):
+		// ER diagrams link to Markdown documents
+		t, ferr := sc.FindTableByName(strings.TrimSuffix(strings.TrimSuffix(p, ".html"), ".md"))
+		if ferr != nil {
+			http.NotFound(w, r)
+			return
+		}
+		err = s.outputTable(w, c, sc, t, version)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Errorf("%s: %+v", r.URL.Path, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) outputSchema(w http.ResponseWriter, c *config.Config, sc *schema.Schema, version int64) error {
+	m := md.New(c, !c.ER.Skip)
+	data := m.SchemaTemplateData(sc)
+	data["version"] = version
+	return s.render(w, c, "index.html.tmpl", data)
+}
+
+func (s *Server) outputTable(w http.ResponseWriter, c *config.Config, sc *schema.Schema, t *schema.Table, version int64) error {
+	m := md.New(c, !c.ER.Skip)
+	m.SetLineage(!c.ER.Skip && t.HasLineage())
+	m.SetUsedBy(sc.ReferencedBy(t))
+	data := m.TableTemplateData(t)
+	data["version"] = version
+	return s.render(w, c, "table.html.tmpl", data)
+}
+
+func (s *Server) outputImage(w http.ResponseWriter, c *config.Config, fn func(g *gviz.Gviz, buf *bytes.Buffer) error) error {
+	if c.ER.Skip {
+		return errors.New("ER diagrams are skipped")
+	}
+	buf := &bytes.Buffer{}
+	if err := fn(gviz.New(c), buf); err != nil {
+		return errors.WithStack(err)
+	}
+	w.Header().Set("Content-Type", "image/svg+xml")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func (s *Server) render(w http.ResponseWriter, c *config.Config, name string, data map[string]interface{}) error {
+	layout, err := s.box.FindString("layout.html.tmpl")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	ts, err := s.box.FindString(name)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	funcs := template.FuncMap{}
+	for k, f := range output.Funcs(&c.MergedDict) {
+		funcs[k] = f
+	}
+	funcs["md"] = mdToHTML
+	funcs["md_list_item"] = mdListItemToHTML
+	tmpl, err := template.New(name).Funcs(funcs).Parse(layout)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := tmpl.Parse(ts); err != nil {
+		return errors.WithStack(err)
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return errors.WithStack(err)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+var (
+	mdLinkRe = regexp.MustCompile(`\[([^\]]*)\]\(([^)]*?)(\.md)?\)`)
+	mdBrRep  = strings.NewReplacer("&lt;br&gt;", "<br>", "\r\n", "<br>", "\n", "<br>", "\\`", "`")
+)
+
+// mdToHTML convert a cell of the Markdown template data ( text and links ) to HTML.
+func mdToHTML(v string) template.HTML {
+	h := mdBrRep.Replace(template.HTMLEscapeString(v))
+	h = mdLinkRe.ReplaceAllStringFunc(h, func(l string) string {
+		m := mdLinkRe.FindStringSubmatch(l)
+		href := m[2]
+		if !safeHref(href) {
+			return l
+		}
+		if m[3] != "" {
+			href += ".html"
+		}
+		return fmt.Sprintf(`<a href="%s">%s</a>`, href, m[1])
+	})
+	return template.HTML(h) // #nosec
+}
+
+// safeHref return true if the href is a relative URL or a http(s) URL.
+func safeHref(href string) bool {
+	u, err := url.Parse(html.UnescapeString(href))
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https":
+		return true
+	}
+	return false
+}
+
+// mdListItemToHTML convert a nested Markdown list item to HTML.
+func mdListItemToHTML(v string) template.HTML {
+	trimmed := strings.TrimLeft(v, " ")
+	depth := (len(v) - len(trimmed)) / 2
+	return template.HTML(fmt.Sprintf(`<li style="margin-left: %dem">%s</li>`, depth*2, mdToHTML(strings.TrimPrefix(trimmed, "- ")))) // #nosec
+}
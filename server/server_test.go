@@ -0,0 +1,86 @@
+package server
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tmdc-io/tbls/config"
+	"github.com/tmdc-io/tbls/schema"
+)
+
+func TestServeHTTP(t *testing.T) {
+	srv := New(func() (*config.Config, *schema.Schema, error) {
+		c, err := config.New()
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := c.Load("", config.ERSkip(true), config.BaseUrl("https://example.com/")); err != nil {
+			return nil, nil, err
+		}
+		return c, newTestSchema(), nil
+	})
+	if err := srv.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	tests := []struct {
+		path   string
+		status int
+		want   []string
+	}{
+		{"/", 200, []string{"<h1>testschema</h1>", `<a href="a.html">a</a>`, `<a href="b.html">b</a>`}},
+		{"/a.html", 200, []string{"<h1>a</h1>", "table a", `<a href="b.html">b</a>`}},
+		{"/b.html", 200, []string{"Used by", `<a href="a.html">a</a>`}},
+		{"/unknown.html", 404, nil},
+		{"/schema.svg", 500, nil},
+	}
+	for _, tt := range tests {
+		res, err := ts.Client().Get(ts.URL + tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if res.StatusCode != tt.status {
+			t.Errorf("%s: got %v\nwant %v", tt.path, res.StatusCode, tt.status)
+		}
+		for _, w := range tt.want {
+			if !strings.Contains(string(b), w) {
+				t.Errorf("%s: got %v\nwant contains %v", tt.path, string(b), w)
+			}
+		}
+	}
+}
+
+func TestMdToHTML(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"[users](users.md) [ext](https://example.com/ext)", `<a href="users.html">users</a> <a href="https://example.com/ext">ext</a>`},
+		{"a<b\nc", "a&lt;b<br>c"},
+		{"[x](javascript:alert(1)) [y](JavaScript:alert&#40;1&#41;) [z](data:text/html,x)", "[x](javascript:alert(1)) [y](JavaScript:alert&amp;#40;1&amp;#41;) [z](data:text/html,x)"},
+		{"[x]( javascript:alert(1))", "[x]( javascript:alert(1))"},
+	}
+	for _, tt := range tests {
+		if got := string(mdToHTML(tt.in)); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func newTestSchema() *schema.Schema {
+	ca := &schema.Column{Name: "a", Type: "int"}
+	cb := &schema.Column{Name: "b", Type: "int"}
+	ta := &schema.Table{Name: "a", Type: "VIEW", Comment: "table a", Columns: []*schema.Column{ca}}
+	tb := &schema.Table{Name: "b", Type: "BASE TABLE", Columns: []*schema.Column{cb}}
+	ta.ReferencedTables = []*schema.Table{tb}
+	return &schema.Schema{
+		Name:   "testschema",
+		Tables: []*schema.Table{ta, tb},
+	}
+}